@@ -5,7 +5,7 @@ type Account struct {
 	// UpCloud account username
 	Username string `json:"username"`
 	// UpCloud account credits
-	Credits string `json:"credits"`
+	Credits StringFloat `json:"credits"`
 }
 
 // getAccountResponse is a response wrapper to match the UpCloud API payload
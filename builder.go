@@ -0,0 +1,294 @@
+package upcloud
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMissingZone is returned when a server is built without a zone
+	ErrMissingZone = errors.New("server zone is required")
+	// ErrMissingHostname is returned when a server is built without a hostname
+	ErrMissingHostname = errors.New("server hostname is required")
+	// ErrMissingStorage is returned when a server is built without any storage devices
+	ErrMissingStorage = errors.New("server requires at least one storage device")
+)
+
+// NewServerBuilder will return a new server builder for the provided zone and hostname
+func NewServerBuilder(zone, hostname string) *ServerBuilder {
+	var b ServerBuilder
+	b.sd.Zone = zone
+	b.sd.Hostname = hostname
+	return &b
+}
+
+// ServerBuilder builds server details for CreateServer
+type ServerBuilder struct {
+	sd ServerDetails
+
+	storageDevices []StorageDevice
+	interfaces     []Interface
+	loginUser      *LoginUser
+	sshKeys        []string
+	tags           []string
+
+	// First error encountered while building
+	err error
+}
+
+// Title will set the title of the server
+// Note: The hostname is used when no title is set
+func (b *ServerBuilder) Title(title string) *ServerBuilder {
+	b.sd.Title = title
+	return b
+}
+
+// Plan will set the plan of the server (e.g. "1xCPU-2GB")
+func (b *ServerBuilder) Plan(plan string) *ServerBuilder {
+	b.sd.Plan = plan
+	return b
+}
+
+// CustomSize will set a custom core number and memory amount (in MB) for the server
+func (b *ServerBuilder) CustomSize(cores, memory int) *ServerBuilder {
+	if cores <= 0 || memory <= 0 {
+		b.setError(fmt.Errorf("invalid custom size of %d cores and %d MB of memory", cores, memory))
+		return b
+	}
+
+	b.sd.Plan = "custom"
+	b.sd.CoreNumber = StringInt(cores)
+	b.sd.MemoryAmount = StringInt(memory)
+	return b
+}
+
+// Timezone will set the timezone of the server (e.g. "Europe/Helsinki")
+func (b *ServerBuilder) Timezone(timezone string) *ServerBuilder {
+	b.sd.Timezone = timezone
+	return b
+}
+
+// CloneTemplate will add a disk cloned from the provided template
+// Note: A size of 0 will use the size of the template or plan
+func (b *ServerBuilder) CloneTemplate(template, title string, size int) *ServerBuilder {
+	return b.CloneTemplateWithTier(template, title, size, "")
+}
+
+// CloneTemplateWithTier will add a disk of the provided tier cloned from the provided template
+// Note: An empty tier will use the UpCloud default tier
+func (b *ServerBuilder) CloneTemplateWithTier(template, title string, size int, tier StorageTier) *ServerBuilder {
+	if template == "" {
+		b.setError(errors.New("template UUID is required for cloning"))
+		return b
+	}
+
+	if tier != "" && !tier.Valid() {
+		b.setError(fmt.Errorf("invalid storage tier \"%s\"", tier))
+		return b
+	}
+
+	var device StorageDevice
+	device.Action = "clone"
+	device.Storage = template
+	device.Title = title
+	device.StorageSize = size
+	device.Tier = tier
+	return b.addStorageDevice(device)
+}
+
+// AddDisk will add a new empty disk of the provided size (in GB) and tier
+// Note: An empty tier will use the UpCloud default tier
+func (b *ServerBuilder) AddDisk(title string, size int, tier StorageTier) *ServerBuilder {
+	if size <= 0 {
+		b.setError(fmt.Errorf("invalid disk size of %d GB", size))
+		return b
+	}
+
+	if tier != "" && !tier.Valid() {
+		b.setError(fmt.Errorf("invalid storage tier \"%s\"", tier))
+		return b
+	}
+
+	var device StorageDevice
+	device.Action = "create"
+	device.Title = title
+	device.StorageSize = size
+	device.Tier = tier
+	return b.addStorageDevice(device)
+}
+
+// AttachStorage will attach an already existing storage
+func (b *ServerBuilder) AttachStorage(storage string) *ServerBuilder {
+	var device StorageDevice
+	device.Action = "attach"
+	device.Storage = storage
+	return b.addStorageDevice(device)
+}
+
+// PublicIPv4 will add a public network interface with an IPv4 address
+func (b *ServerBuilder) PublicIPv4() *ServerBuilder {
+	return b.addInterface("public", "", IPv4)
+}
+
+// PublicIPv6 will add a public network interface with an IPv6 address
+func (b *ServerBuilder) PublicIPv6() *ServerBuilder {
+	return b.addInterface("public", "", IPv6)
+}
+
+// UtilityNetwork will add a utility network interface
+func (b *ServerBuilder) UtilityNetwork() *ServerBuilder {
+	return b.addInterface("utility", "", IPv4)
+}
+
+// PrivateNetwork will add a network interface attached to the provided private network
+func (b *ServerBuilder) PrivateNetwork(network string) *ServerBuilder {
+	if network == "" {
+		b.setError(errors.New("network UUID is required for private networks"))
+		return b
+	}
+
+	return b.addInterface("private", network, IPv4)
+}
+
+// UserData will set the user data (e.g. cloud-init configuration) of the server
+// Note: This enables the metadata service, which cloud-init reads the user data from
+func (b *ServerBuilder) UserData(userData string) *ServerBuilder {
+	b.sd.UserData = userData
+	b.sd.Metadata = NewYesNo(true)
+	return b
+}
+
+// Metadata will enable or disable the metadata service of the server
+func (b *ServerBuilder) Metadata(enabled bool) *ServerBuilder {
+	b.sd.Metadata = NewYesNo(enabled)
+	return b
+}
+
+// LoginUser will set the user created on the server and whether or not a password is generated for it
+// Note: The generated password is returned within the CreateServer response
+func (b *ServerBuilder) LoginUser(username string, createPassword bool) *ServerBuilder {
+	b.loginUser = &LoginUser{
+		Username:       username,
+		CreatePassword: NewYesNo(createPassword),
+	}
+
+	return b
+}
+
+// SSHKeys will add SSH public keys to be injected into the server
+func (b *ServerBuilder) SSHKeys(keys ...string) *ServerBuilder {
+	for _, key := range keys {
+		if err := ValidateSSHKey(key); err != nil {
+			b.setError(err)
+			return b
+		}
+	}
+
+	b.sshKeys = append(b.sshKeys, keys...)
+	return b
+}
+
+// AuthorizedKeys will add the SSH public keys of an authorized_keys file to be injected into the server
+func (b *ServerBuilder) AuthorizedKeys(filename string) *ServerBuilder {
+	keys, err := ReadAuthorizedKeys(filename)
+	if err != nil {
+		b.setError(err)
+		return b
+	}
+
+	b.sshKeys = append(b.sshKeys, keys...)
+	return b
+}
+
+// Tags will add tags to the server
+func (b *ServerBuilder) Tags(tags ...string) *ServerBuilder {
+	b.tags = append(b.tags, tags...)
+	return b
+}
+
+// Build will validate and return the server details
+func (b *ServerBuilder) Build() (sd *ServerDetails, err error) {
+	if b.err != nil {
+		err = b.err
+		return
+	}
+
+	switch {
+	case b.sd.Zone == "":
+		err = ErrMissingZone
+	case b.sd.Hostname == "":
+		err = ErrMissingHostname
+	case len(b.storageDevices) == 0:
+		err = ErrMissingStorage
+	}
+
+	if err != nil {
+		return
+	}
+
+	// Copy the server details so the builder can be reused
+	var out = b.sd
+	if out.Title == "" {
+		out.Title = out.Hostname
+	}
+
+	var storageDevices = append([]StorageDevice(nil), b.storageDevices...)
+	out.StorageDevices = &StorageDevices{StorageDevice: &storageDevices}
+
+	if len(b.interfaces) > 0 {
+		// Interfaces are only set when requested, otherwise UpCloud applies the default networking
+		var interfaces = make([]Interface, len(b.interfaces))
+		for i, iface := range b.interfaces {
+			var ipAddresses = append([]IPAddress(nil), *iface.IPAddresses.IPAddress...)
+			iface.IPAddresses = &IPAddresses{IPAddress: &ipAddresses}
+			interfaces[i] = iface
+		}
+
+		out.Networking = &Networking{Interfaces: &Interfaces{Interface: &interfaces}}
+	}
+
+	if b.loginUser != nil {
+		var loginUser = *b.loginUser
+		out.LoginUser = &loginUser
+	}
+
+	if len(b.sshKeys) > 0 {
+		if out.LoginUser == nil {
+			out.LoginUser = &LoginUser{}
+		}
+
+		var sshKeys = append([]string(nil), b.sshKeys...)
+		out.LoginUser.SSHKeys = &SSHKeys{SSHKey: &sshKeys}
+	}
+
+	if len(b.tags) > 0 {
+		var tags = append([]string(nil), b.tags...)
+		out.Tags = &Tags{Tag: &tags}
+	}
+
+	sd = &out
+	return
+}
+
+func (b *ServerBuilder) addStorageDevice(device StorageDevice) *ServerBuilder {
+	b.storageDevices = append(b.storageDevices, device)
+	return b
+}
+
+func (b *ServerBuilder) addInterface(interfaceType, network string, family IPAddressFamily) *ServerBuilder {
+	var iface Interface
+	iface.Type = interfaceType
+	iface.Network = network
+	iface.IPAddresses = &IPAddresses{IPAddress: &[]IPAddress{{Family: family}}}
+	b.interfaces = append(b.interfaces, iface)
+	return b
+}
+
+func (b *ServerBuilder) setError(err error) {
+	if b.err != nil {
+		// Only the first error is retained
+		return
+	}
+
+	b.err = err
+}
@@ -0,0 +1,203 @@
+package upcloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is the number of servers processed at once by bulk operations
+const DefaultConcurrency = 5
+
+// ServerFilter will return whether or not a server is processed by ForEachServer
+type ServerFilter func(s *Server) bool
+
+// ServerFunc is called by ForEachServer for each server matching the filter
+type ServerFunc func(ctx context.Context, s Server) error
+
+// BulkOptions represents the options of bulk operations
+type BulkOptions struct {
+	// Number of servers processed at once, DefaultConcurrency is used when not set
+	Concurrency int
+	// Wait for each server to reach its final state (e.g. stopped for BulkStop)
+	// Note: The context bounds how long to wait
+	Wait bool
+}
+
+// BulkResult represents the result of a bulk operation for a single server
+type BulkResult struct {
+	UUID string
+	Err  error
+}
+
+// BulkError is returned when a bulk operation fails for some of the servers
+type BulkError struct {
+	// Failed results, in the order of the servers
+	Failed []BulkResult
+	// Total number of servers of the operation
+	Total int
+}
+
+// Error will return the failures as a single message
+func (e *BulkError) Error() string {
+	var failures = make([]string, 0, len(e.Failed))
+	for _, r := range e.Failed {
+		failures = append(failures, fmt.Sprintf("%s: %v", r.UUID, r.Err))
+	}
+
+	return fmt.Sprintf("%d of %d servers failed: %s", len(e.Failed), e.Total, strings.Join(failures, "; "))
+}
+
+// Errors will return the error of every failed server
+func (e *BulkError) Errors() (errs []error) {
+	for _, r := range e.Failed {
+		errs = append(errs, r.Err)
+	}
+
+	return
+}
+
+// FilterByTag will return a filter matching servers carrying the tag
+// Note: Tags are case-insensitive
+func FilterByTag(tag string) ServerFilter {
+	return func(s *Server) bool {
+		return hasTag(s.Tags.List(), tag)
+	}
+}
+
+// FilterByZone will return a filter matching servers within the zone
+func FilterByZone(zone string) ServerFilter {
+	return func(s *Server) bool {
+		return s.Zone == zone
+	}
+}
+
+// FilterByState will return a filter matching servers in the state
+func FilterByState(state ServerState) ServerFilter {
+	return func(s *Server) bool {
+		return s.State == state
+	}
+}
+
+// ForEachServer will call fn for every server matching the filter, with at most concurrency calls at once
+// Note: A nil filter matches every server. Results are in the order of the servers, a *BulkError is returned
+// when any call fails. Once the context is done, the remaining servers fail with the context error
+func (u *UpCloud) ForEachServer(ctx context.Context, filter ServerFilter, fn ServerFunc, concurrency int) (results []BulkResult, err error) {
+	var servers *[]Server
+	if servers, err = u.GetServers(); err != nil {
+		return
+	}
+
+	var matched []Server
+	for i := range *servers {
+		if filter == nil || filter(&(*servers)[i]) {
+			matched = append(matched, (*servers)[i])
+		}
+	}
+
+	var uuids = make([]string, 0, len(matched))
+	for _, s := range matched {
+		uuids = append(uuids, s.UUID)
+	}
+
+	return forEach(ctx, uuids, concurrency, func(ctx context.Context, i int) error {
+		return fn(ctx, matched[i])
+	})
+}
+
+// BulkStop will stop the servers
+// Note: Servers which are already stopped are skipped
+func (u *UpCloud) BulkStop(ctx context.Context, uuids []string, stop StopServer, options BulkOptions) (results []BulkResult, err error) {
+	return forEach(ctx, uuids, options.Concurrency, func(ctx context.Context, i int) error {
+		return u.changeState(ctx, uuids[i], ServerStateStopped, options.Wait, func() (err error) {
+			_, err = u.StopServer(uuids[i], stop)
+			return
+		})
+	})
+}
+
+// BulkStart will start the servers
+// Note: Servers which are already started are skipped
+func (u *UpCloud) BulkStart(ctx context.Context, uuids []string, start StartServer, options BulkOptions) (results []BulkResult, err error) {
+	return forEach(ctx, uuids, options.Concurrency, func(ctx context.Context, i int) error {
+		return u.changeState(ctx, uuids[i], ServerStateStarted, options.Wait, func() (err error) {
+			_, err = u.StartServer(uuids[i], start)
+			return
+		})
+	})
+}
+
+// BulkDelete will stop and delete the servers, along with their storages when deleteStorage is set
+// Note: Servers carrying the protection tag fail with ErrServerProtected. Deletion is synchronous, so Wait has no effect
+func (u *UpCloud) BulkDelete(ctx context.Context, uuids []string, deleteStorage bool, options BulkOptions) (results []BulkResult, err error) {
+	return forEach(ctx, uuids, options.Concurrency, func(ctx context.Context, i int) error {
+		return u.DeleteServerWithOptions(ctx, uuids[i], DeleteServer{Storages: deleteStorage, Stop: true})
+	})
+}
+
+// changeState will call change unless the server is already in the state, waiting for the state when wait is set
+func (u *UpCloud) changeState(ctx context.Context, uuid string, state ServerState, wait bool, change func() error) (err error) {
+	var sd *ServerDetails
+	if sd, err = u.GetServerDetails(uuid); err != nil || sd.State == state {
+		return
+	}
+
+	if err = change(); err != nil || !wait {
+		return
+	}
+
+	_, err = u.WaitForState(ctx, uuid, state)
+	return
+}
+
+// forEach will call fn for the index of every UUID, with at most concurrency calls at once
+func forEach(ctx context.Context, uuids []string, concurrency int, fn func(ctx context.Context, i int) error) (results []BulkResult, err error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results = make([]BulkResult, len(uuids))
+
+	var wg sync.WaitGroup
+	var sem = make(chan struct{}, concurrency)
+	for i, uuid := range uuids {
+		results[i].UUID = uuid
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Both cases of the select above may be ready, so the context is checked again
+			if results[i].Err = ctx.Err(); results[i].Err != nil {
+				return
+			}
+
+			results[i].Err = fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	var bulkErr BulkError
+	bulkErr.Total = len(results)
+	for _, r := range results {
+		if r.Err != nil {
+			bulkErr.Failed = append(bulkErr.Failed, r)
+		}
+	}
+
+	if len(bulkErr.Failed) > 0 {
+		err = &bulkErr
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+package upcloud_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func TestUpCloud_BulkStop(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+	srv.SetTransitionDelay(5 * time.Millisecond)
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var uuids []string
+	for i := 0; i < 4; i++ {
+		uuids = append(uuids, createServer(t, u).UUID)
+	}
+
+	ctx := context.Background()
+	if _, err = u.BulkStart(ctx, uuids, upcloud.StartServer{}, upcloud.BulkOptions{}); err == nil {
+		t.Fatal("expected error starting servers which are not stopped")
+	}
+
+	for _, uuid := range uuids {
+		if _, err = u.WaitForState(ctx, uuid, upcloud.ServerStateStarted); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first stop fails, the remaining servers are still stopped
+	srv.Fail(upcloudtest.Failure{Method: "POST", Path: "server/" + uuids[0] + "/stop", StatusCode: http.StatusConflict, ErrorCode: "SERVER_STATE_ILLEGAL", Times: 1})
+
+	var results []upcloud.BulkResult
+	results, err = u.BulkStop(ctx, uuids, upcloud.StopServer{StopType: string(upcloud.Hard)}, upcloud.BulkOptions{Concurrency: 2, Wait: true})
+
+	var bulkErr *upcloud.BulkError
+	if !errors.As(err, &bulkErr) || len(bulkErr.Failed) != 1 || bulkErr.Failed[0].UUID != uuids[0] {
+		t.Fatalf("invalid error, expected a single failure of %s and received %v", uuids[0], err)
+	}
+
+	if len(results) != len(uuids) || results[1].UUID != uuids[1] || results[1].Err != nil {
+		t.Fatalf("invalid results, received %+v", results)
+	}
+
+	for _, s := range srv.Servers()[1:] {
+		if s.State != upcloud.ServerStateStopped {
+			t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", upcloud.ServerStateStopped, s.State)
+		}
+	}
+
+	// Stopped servers are skipped rather than failing with an illegal state
+	if _, err = u.BulkStop(ctx, uuids[1:], upcloud.StopServer{}, upcloud.BulkOptions{Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.BulkDelete(ctx, uuids[1:], true, upcloud.BulkOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Servers()); n != 1 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 1, n)
+	}
+}
+
+func TestUpCloud_ForEachServer(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	for i := 0; i < 6; i++ {
+		createServer(t, u)
+	}
+
+	var running, peak int32
+	fn := func(ctx context.Context, s upcloud.Server) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+
+	var results []upcloud.BulkResult
+	if results, err = u.ForEachServer(context.Background(), upcloud.FilterByZone("us-chi1"), fn, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 6 {
+		t.Fatalf("invalid number of results, expected %d and received %d", 6, len(results))
+	}
+
+	if peak > 2 {
+		t.Fatalf("invalid concurrency, expected at most %d and received %d", 2, peak)
+	}
+
+	if results, err = u.ForEachServer(context.Background(), upcloud.FilterByZone("de-fra1"), fn, 2); err != nil || len(results) != 0 {
+		t.Fatalf("invalid results, expected none and received %+v (%v)", results, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = u.ForEachServer(ctx, nil, fn, 2)
+	var bulkErr *upcloud.BulkError
+	if !errors.As(err, &bulkErr) || len(bulkErr.Failed) != 6 || bulkErr.Failed[0].Err != context.Canceled {
+		t.Fatalf("invalid error, expected every server to be canceled and received %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package upcloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a request is short-circuited by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open, UpCloud API requests are suspended")
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures which opens the circuit
+	DefaultFailureThreshold = 5
+	// DefaultOpenTimeout is how long the circuit stays open before probing
+	DefaultOpenTimeout = 30 * time.Second
+	// DefaultHalfOpenProbes is the number of probe requests allowed while half-open
+	DefaultHalfOpenProbes = 1
+)
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen short-circuits every request with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through
+	CircuitHalfOpen
+)
+
+// String will return the string representation of the circuit state
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions represents the optional parameters of a circuit breaker
+// Note: Zero values are replaced with their defaults
+type CircuitBreakerOptions struct {
+	// Consecutive failures which open the circuit
+	FailureThreshold int
+	// Duration the circuit stays open before half-opening
+	OpenTimeout time.Duration
+	// Probe requests allowed while half-open, the circuit closes once all of them succeed
+	HalfOpenProbes int
+}
+
+// NewCircuitBreaker will return a new circuit breaker
+func NewCircuitBreaker(options CircuitBreakerOptions) *CircuitBreaker {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = DefaultFailureThreshold
+	}
+
+	if options.OpenTimeout <= 0 {
+		options.OpenTimeout = DefaultOpenTimeout
+	}
+
+	if options.HalfOpenProbes <= 0 {
+		options.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+
+	var c CircuitBreaker
+	c.options = options
+	c.now = time.Now
+	return &c
+}
+
+// CircuitBreaker stops requests to the UpCloud API after consecutive failures
+// Note: Transport errors, 429 and 5xx responses are failures, other responses are successes
+type CircuitBreaker struct {
+	mux sync.Mutex
+
+	options CircuitBreakerOptions
+	now     func() time.Time
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// Probes in flight and probes succeeded while half-open
+	probes    int
+	successes int
+}
+
+// SetCircuitBreaker will protect requests with the provided circuit breaker, replacing the current one
+// Note: A nil circuit breaker disables it. The circuit breaker wraps the request within the middlewares (see Use)
+func (u *UpCloud) SetCircuitBreaker(c *CircuitBreaker) {
+	u.circuitBreaker = c
+}
+
+// Middleware will return a middleware which short-circuits requests while the circuit is open
+func (c *CircuitBreaker) Middleware() Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(req *Request) (res *http.Response, err error) {
+			var probe bool
+			if probe, err = c.allow(); err != nil {
+				return
+			}
+
+			res, err = next(req)
+			c.record(probe, isFailure(res, err))
+			return
+		}
+	}
+}
+
+// State will return the current state of the circuit, for use within health checks
+func (c *CircuitBreaker) State() CircuitState {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.refresh()
+	return c.state
+}
+
+// Reset will close the circuit and clear the failure count
+func (c *CircuitBreaker) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.close()
+}
+
+// allow will return whether or not the request is a probe, or ErrCircuitOpen when it is not allowed
+func (c *CircuitBreaker) allow() (probe bool, err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.refresh()
+
+	switch c.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.probes+c.successes >= c.options.HalfOpenProbes {
+			err = ErrCircuitOpen
+			return
+		}
+
+		c.probes++
+		probe = true
+	}
+
+	return
+}
+
+func (c *CircuitBreaker) record(probe, failure bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if probe && c.probes > 0 {
+		c.probes--
+	}
+
+	switch {
+	case c.state == CircuitHalfOpen && !probe:
+		// Requests started before the circuit opened do not affect probing
+	case c.state == CircuitHalfOpen && failure:
+		c.open()
+	case c.state == CircuitHalfOpen:
+		if c.successes++; c.successes >= c.options.HalfOpenProbes {
+			c.close()
+		}
+	case c.state == CircuitOpen:
+		// Requests started before the circuit opened do not affect the open circuit
+	case failure:
+		if c.failures++; c.failures >= c.options.FailureThreshold {
+			c.open()
+		}
+	default:
+		c.failures = 0
+	}
+}
+
+// refresh will half-open the circuit once the open timeout has elapsed
+func (c *CircuitBreaker) refresh() {
+	if c.state == CircuitOpen && c.now().Sub(c.openedAt) >= c.options.OpenTimeout {
+		c.state = CircuitHalfOpen
+		c.probes = 0
+		c.successes = 0
+	}
+}
+
+func (c *CircuitBreaker) open() {
+	c.state = CircuitOpen
+	c.openedAt = c.now()
+	c.failures = 0
+}
+
+func (c *CircuitBreaker) close() {
+	c.state = CircuitClosed
+	c.failures = 0
+	c.probes = 0
+	c.successes = 0
+}
+
+// isFailure will return whether or not the outcome of a request indicates an unhealthy API
+// Note: Cancelled requests are not failures as they say nothing about the API
+func isFailure(res *http.Response, err error) bool {
+	switch {
+	case err != nil:
+		return !errors.Is(err, context.Canceled)
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return res.StatusCode >= 500
+	}
+}
@@ -0,0 +1,145 @@
+package upcloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hatchify/requester"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var err error
+	var healthy bool
+	var requests int
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"error_code":"SERVICE_UNAVAILABLE","error_message":"Service unavailable."}}`))
+			return
+		}
+
+		w.Write([]byte(`{"account":{"username":"username"}}`))
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	var now = time.Now()
+	var c = NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 2, OpenTimeout: time.Minute})
+	c.now = func() time.Time { return now }
+	u.SetCircuitBreaker(c)
+
+	for i := 0; i < 2; i++ {
+		if _, err = u.GetAccount(); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("invalid error, expected an API error and received %v", err)
+		}
+	}
+
+	if state := c.State(); state != CircuitOpen {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitOpen, state)
+	}
+
+	if _, err = u.GetAccount(); err != ErrCircuitOpen {
+		t.Fatalf("invalid error, expected %v and received %v", ErrCircuitOpen, err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("invalid number of requests, expected %d and received %d", 2, requests)
+	}
+
+	now = now.Add(time.Minute)
+	if state := c.State(); state != CircuitHalfOpen {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitHalfOpen, state)
+	}
+
+	// A failed probe opens the circuit again
+	if _, err = u.GetAccount(); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("invalid error, expected an API error and received %v", err)
+	}
+
+	if state := c.State(); state != CircuitOpen {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitOpen, state)
+	}
+
+	now = now.Add(time.Minute)
+	healthy = true
+	if _, err = u.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := c.State(); state != CircuitClosed {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitClosed, state)
+	}
+}
+
+func TestUpCloud_SetCircuitBreaker(t *testing.T) {
+	var err error
+	var requests int
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"error_code":"SERVICE_UNAVAILABLE","error_message":"Service unavailable."}}`))
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	// The second circuit breaker replaces the first one
+	var replaced = NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 1})
+	var c = NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 1})
+	u.SetCircuitBreaker(replaced)
+	u.SetCircuitBreaker(c)
+
+	if _, err = u.GetAccount(); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("invalid error, expected an API error and received %v", err)
+	}
+
+	if state := replaced.State(); state != CircuitClosed {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitClosed, state)
+	}
+
+	if state := c.State(); state != CircuitOpen {
+		t.Fatalf("invalid state, expected %s and received %s", CircuitOpen, state)
+	}
+
+	// A nil circuit breaker disables it
+	u.SetCircuitBreaker(nil)
+	if _, err = u.GetAccount(); err == nil || err == ErrCircuitOpen {
+		t.Fatalf("invalid error, expected an API error and received %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("invalid number of requests, expected %d and received %d", 2, requests)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	var tests = []struct {
+		status  int
+		failure bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusBadGateway, true},
+	}
+
+	for _, test := range tests {
+		if failure := isFailure(&http.Response{StatusCode: test.status}, nil); failure != test.failure {
+			t.Fatalf("invalid failure for %d, expected %v and received %v", test.status, test.failure, failure)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package upcloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/hatchify/requester"
+)
+
+var _ Client = &UpCloud{}
+
+// Client is the interface of every public method of UpCloud, use it in place of *UpCloud to allow mocking
+// Note: See upcloudtest.MockClient for a ready-made mock, TestClient fails when a method is missing
+type Client interface {
+	SetRequester(newReq requester.Interface)
+	SetLogger(logger Logger)
+	SetDebug(debug bool)
+	SetTracer(tracer Tracer)
+	SetMetrics(metrics Metrics)
+	SetCircuitBreaker(c *CircuitBreaker)
+	Use(middlewares ...Middleware)
+	SetPollInterval(interval time.Duration)
+
+	GetAccount() (a *Account, err error)
+	GetZones() (z *[]Zone, err error)
+	GetZone(id string) (z *Zone, err error)
+	ZonesByCountry() (zc map[string][]Zone, err error)
+	GetPlans() (p *[]Plan, err error)
+	GetTimezones() (t *[]string, err error)
+	GetPrices() (p *[]PriceZone, err error)
+	GetServerSizes() (p *[]ServerSize, err error)
+	EstimateServerCost(serverDetails *ServerDetails, zone string, hours int) (e *CostEstimate, err error)
+	FindTemplate(query TemplateQuery) (uuids map[string]string, err error)
+	ResolveTemplate(template, zone string) (uuid string, err error)
+
+	GetServers() (p *[]Server, err error)
+	GetServersWithOptions(options ListOptions) (p *[]Server, err error)
+	NewServerIterator(options ListOptions) *ServerIterator
+	GetServerDetails(uuid string) (p *ServerDetails, err error)
+	GetStorages(filter RouteGetStorageFilter) (p *[]Storage, err error)
+	GetStoragesWithOptions(filter RouteGetStorageFilter, options ListOptions) (p *[]Storage, err error)
+	NewStorageIterator(filter RouteGetStorageFilter, options ListOptions) *StorageIterator
+	GetFirewallRules(uuid string) (p *[]FirewallRule, err error)
+	GetNetworks() (p *[]Network, err error)
+	ExportAccountState(ctx context.Context) (s *AccountState, err error)
+
+	CreateServer(serverDetails *ServerDetails) (p *ServerDetails, err error)
+	ModifyServer(uuid string, serverDetails *ServerDetails) (s *ServerDetails, err error)
+	SetMetadata(uuid string, enabled bool) (s *ServerDetails, err error)
+	TagServer(uuid string, tags ...string) (s *ServerDetails, err error)
+	UntagServer(uuid string, tags ...string) (s *ServerDetails, err error)
+	StopServer(uuid string, options StopServer) (s *ServerDetails, err error)
+	StartServer(uuid string, options StartServer) (s *ServerDetails, err error)
+	DeleteServer(uuid string, deleteStorage bool) (err error)
+	DeleteServerWithOptions(ctx context.Context, uuid string, options DeleteServer) (err error)
+	WaitForState(ctx context.Context, uuid string, state ServerState) (s *ServerDetails, err error)
+	ForEachServer(ctx context.Context, filter ServerFilter, fn ServerFunc, concurrency int) (results []BulkResult, err error)
+	BulkStop(ctx context.Context, uuids []string, stop StopServer, options BulkOptions) (results []BulkResult, err error)
+	BulkStart(ctx context.Context, uuids []string, start StartServer, options BulkOptions) (results []BulkResult, err error)
+	BulkDelete(ctx context.Context, uuids []string, deleteStorage bool, options BulkOptions) (results []BulkResult, err error)
+
+	CreateStorage(options CreateStorage) (s *Storage, err error)
+	DeleteStorage(uuid string) (err error)
+
+	PlanReconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error)
+	Reconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error)
+}
@@ -0,0 +1,17 @@
+package upcloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	var client = reflect.TypeOf((*Client)(nil)).Elem()
+	var u = reflect.TypeOf(&UpCloud{})
+	for i := 0; i < u.NumMethod(); i++ {
+		var method = u.Method(i)
+		if _, ok := client.MethodByName(method.Name); !ok {
+			t.Errorf("method %s of UpCloud is missing from Client, add it and run go generate ./...", method.Name)
+		}
+	}
+}
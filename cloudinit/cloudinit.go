@@ -0,0 +1,134 @@
+// Package cloudinit composes cloud-init user data for UpCloud servers
+package cloudinit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	// MaxSize is the maximum size of user data accepted by UpCloud (in bytes)
+	MaxSize = 16 * 1024
+
+	// Boundary is the MIME boundary used for multipart user data
+	// Note: A fixed boundary keeps the rendered output stable for review
+	Boundary = "==UPCLOUD-SDK-USER-DATA=="
+)
+
+const (
+	contentTypeCloudConfig = "text/cloud-config"
+	contentTypeShellScript = "text/x-shellscript"
+)
+
+var (
+	// ErrEmpty is returned when composing user data without any contents
+	ErrEmpty = errors.New("user data requires a cloud config or at least one script")
+	// ErrTooLarge is returned when the composed user data exceeds MaxSize
+	ErrTooLarge = fmt.Errorf("user data exceeds the maximum size of %d bytes", MaxSize)
+)
+
+// Script represents a shell script executed on first boot
+type Script struct {
+	// Name of the script, used as the attachment filename
+	Name string
+	// Content of the script, must begin with an interpreter directive (e.g. "#!/bin/sh")
+	Content string
+}
+
+// Compose will compose the user data payload for the provided cloud config and scripts
+// Note: A single cloud config or script is rendered as-is, otherwise a multipart MIME message is rendered
+func Compose(config *Config, scripts ...Script) (userData string, err error) {
+	var parts []part
+	if config != nil {
+		var rendered string
+		if rendered, err = config.Render(); err != nil {
+			return
+		}
+
+		parts = append(parts, part{contentType: contentTypeCloudConfig, filename: "cloud-config.txt", content: rendered})
+	}
+
+	for i, script := range scripts {
+		if !strings.HasPrefix(script.Content, "#!") {
+			err = fmt.Errorf("script \"%s\" is missing an interpreter directive (e.g. \"#!/bin/sh\")", script.Name)
+			return
+		}
+
+		var filename = script.Name
+		if filename == "" {
+			filename = fmt.Sprintf("script-%d.sh", i+1)
+		}
+
+		parts = append(parts, part{contentType: contentTypeShellScript, filename: filename, content: script.Content})
+	}
+
+	switch len(parts) {
+	case 0:
+		err = ErrEmpty
+		return
+	case 1:
+		userData = parts[0].content
+	default:
+		if userData, err = renderMultipart(parts); err != nil {
+			return
+		}
+	}
+
+	if len(userData) > MaxSize {
+		err = ErrTooLarge
+		userData = ""
+	}
+
+	return
+}
+
+type part struct {
+	contentType string
+	filename    string
+	content     string
+}
+
+func renderMultipart(parts []part) (out string, err error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", Boundary)
+	buf.WriteString("MIME-Version: 1.0\r\n\r\n")
+
+	var w = multipart.NewWriter(&buf)
+	if err = w.SetBoundary(Boundary); err != nil {
+		return
+	}
+
+	for _, p := range parts {
+		if strings.Contains(p.content, Boundary) {
+			err = fmt.Errorf("part \"%s\" contains the MIME boundary", p.filename)
+			return
+		}
+
+		var header = make(textproto.MIMEHeader)
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", p.contentType))
+		header.Set("Content-Transfer-Encoding", "7bit")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", p.filename))
+		header.Set("MIME-Version", "1.0")
+
+		var pw io.Writer
+		if pw, err = w.CreatePart(header); err != nil {
+			return
+		}
+
+		if _, err = pw.Write([]byte(p.content)); err != nil {
+			return
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return
+	}
+
+	out = buf.String()
+	return
+}
@@ -0,0 +1,146 @@
+package cloudinit
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfig_Render(t *testing.T) {
+	var err error
+	var lock = false
+	var c = Config{
+		Hostname:        "web-1",
+		KeepDefaultUser: true,
+		Users: []User{{
+			Name:              "deploy",
+			Groups:            []string{"sudo"},
+			Shell:             "/bin/bash",
+			LockPasswd:        &lock,
+			SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA deploy@example"},
+		}},
+		PackageUpdate: true,
+		Packages:      []string{"nginx"},
+		WriteFiles: []File{{
+			Path:        "/etc/motd",
+			Permissions: "0644",
+			Content:     "Welcome\n\n  to web-1\n",
+		}},
+		RunCmd: []string{"systemctl restart nginx"},
+	}
+
+	var out string
+	if out, err = c.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `#cloud-config
+hostname: "web-1"
+users:
+  - "default"
+  - name: "deploy"
+    groups:
+      - "sudo"
+    shell: "/bin/bash"
+    lock_passwd: false
+    ssh_authorized_keys:
+      - "ssh-ed25519 AAAA deploy@example"
+package_update: true
+packages:
+  - "nginx"
+write_files:
+  - path: "/etc/motd"
+    permissions: "0644"
+    content: |
+      Welcome
+
+        to web-1
+runcmd:
+  - "systemctl restart nginx"
+`
+
+	if out != expected {
+		t.Fatalf("invalid cloud config, expected:\n%s\nreceived:\n%s", expected, out)
+	}
+}
+
+func TestConfig_RenderBlocks(t *testing.T) {
+	var err error
+	var contents = []string{
+		"  indented\nfirst line\n",
+		"\n  indented after an empty line\n",
+		"   \n  indented after a blank line",
+		"plain\n\n\n",
+	}
+
+	var c Config
+	for _, content := range contents {
+		c.WriteFiles = append(c.WriteFiles, File{Path: "/tmp/file", Content: content})
+	}
+
+	var out string
+	if out, err = c.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed struct {
+		WriteFiles []struct {
+			Content string `yaml:"content"`
+		} `yaml:"write_files"`
+	}
+
+	if err = yaml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid cloud config, %v:\n%s", err, out)
+	}
+
+	for i, content := range contents {
+		if received := parsed.WriteFiles[i].Content; received != content {
+			t.Fatalf("invalid content, expected %q and received %q:\n%s", content, received, out)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	var err error
+	var c = Config{Packages: []string{"curl"}}
+
+	var userData string
+	// A single cloud config is rendered as-is
+	if userData, err = Compose(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(userData, "#cloud-config\n") {
+		t.Fatalf("invalid user data, received %s", userData)
+	}
+
+	// Cloud configs along with scripts are rendered as multipart MIME
+	if userData, err = Compose(&c, Script{Name: "setup.sh", Content: "#!/bin/sh\necho hello\n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"Content-Type: multipart/mixed; boundary=\"" + Boundary + "\"",
+		"Content-Type: text/cloud-config",
+		"Content-Type: text/x-shellscript",
+		"filename=\"setup.sh\"",
+		"--" + Boundary + "--",
+	} {
+		if !strings.Contains(userData, expected) {
+			t.Fatalf("user data is missing \"%s\":\n%s", expected, userData)
+		}
+	}
+
+	if _, err = Compose(nil); err != ErrEmpty {
+		t.Fatalf("invalid error, expected %v and received %v", ErrEmpty, err)
+	}
+
+	if _, err = Compose(nil, Script{Content: "echo missing interpreter"}); err == nil {
+		t.Fatal("expected error for script without an interpreter directive")
+	}
+
+	if _, err = Compose(nil, Script{Content: "#!/bin/sh\n" + strings.Repeat("#", MaxSize)}); err != ErrTooLarge {
+		t.Fatalf("invalid error, expected %v and received %v", ErrTooLarge, err)
+	}
+}
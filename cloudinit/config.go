@@ -0,0 +1,225 @@
+package cloudinit
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Config represents a cloud-init cloud config
+type Config struct {
+	Hostname string
+	Timezone string
+
+	// Keep the default user of the image along with Users
+	KeepDefaultUser bool
+	Users           []User
+
+	PackageUpdate  bool
+	PackageUpgrade bool
+	Packages       []string
+
+	WriteFiles []File
+	RunCmd     []string
+}
+
+// User represents a user created by cloud-init
+type User struct {
+	Name   string
+	Groups []string
+	Shell  string
+	// Sudo rule of the user (e.g. "ALL=(ALL) NOPASSWD:ALL")
+	Sudo              string
+	SSHAuthorizedKeys []string
+	// Lock the password of the user, cloud-init defaults to locking
+	LockPasswd *bool
+}
+
+// File represents a file written by cloud-init
+type File struct {
+	Path    string
+	Content string
+	// Permissions of the file in octal notation (e.g. "0644")
+	Permissions string
+	// Owner of the file (e.g. "root:root")
+	Owner  string
+	Append bool
+}
+
+// Render will render the cloud config including the "#cloud-config" header
+func (c *Config) Render() (out string, err error) {
+	var w yamlWriter
+	w.buf.WriteString("#cloud-config\n")
+
+	w.scalar(0, "hostname", c.Hostname)
+	w.scalar(0, "timezone", c.Timezone)
+
+	if c.KeepDefaultUser || len(c.Users) > 0 {
+		w.key(0, "users")
+		if c.KeepDefaultUser {
+			w.item(1, "default")
+		}
+
+		for _, u := range c.Users {
+			if u.Name == "" {
+				err = fmt.Errorf("user name is required")
+				return
+			}
+
+			w.itemKey(1, "name", u.Name)
+			w.list(2, "groups", u.Groups)
+			w.scalar(2, "shell", u.Shell)
+			w.scalar(2, "sudo", u.Sudo)
+			if u.LockPasswd != nil {
+				w.boolean(2, "lock_passwd", *u.LockPasswd)
+			}
+
+			w.list(2, "ssh_authorized_keys", u.SSHAuthorizedKeys)
+		}
+	}
+
+	if c.PackageUpdate {
+		w.boolean(0, "package_update", true)
+	}
+
+	if c.PackageUpgrade {
+		w.boolean(0, "package_upgrade", true)
+	}
+
+	w.list(0, "packages", c.Packages)
+
+	if len(c.WriteFiles) > 0 {
+		w.key(0, "write_files")
+		for _, f := range c.WriteFiles {
+			if f.Path == "" {
+				err = fmt.Errorf("file path is required")
+				return
+			}
+
+			w.itemKey(1, "path", f.Path)
+			w.scalar(2, "permissions", f.Permissions)
+			w.scalar(2, "owner", f.Owner)
+			if f.Append {
+				w.boolean(2, "append", true)
+			}
+
+			w.block(2, "content", f.Content)
+		}
+	}
+
+	w.list(0, "runcmd", c.RunCmd)
+
+	out = w.buf.String()
+	return
+}
+
+// yamlWriter writes the subset of YAML needed for cloud configs
+// Note: Scalars are always double quoted so no value can be misinterpreted
+type yamlWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *yamlWriter) indent(level int) {
+	w.buf.WriteString(strings.Repeat("  ", level))
+}
+
+func (w *yamlWriter) key(level int, key string) {
+	w.indent(level)
+	w.buf.WriteString(key)
+	w.buf.WriteString(":\n")
+}
+
+func (w *yamlWriter) scalar(level int, key, value string) {
+	if value == "" {
+		return
+	}
+
+	w.indent(level)
+	fmt.Fprintf(&w.buf, "%s: %s\n", key, strconv.Quote(value))
+}
+
+func (w *yamlWriter) boolean(level int, key string, value bool) {
+	w.indent(level)
+	fmt.Fprintf(&w.buf, "%s: %t\n", key, value)
+}
+
+func (w *yamlWriter) item(level int, value string) {
+	w.indent(level)
+	fmt.Fprintf(&w.buf, "- %s\n", strconv.Quote(value))
+}
+
+// itemKey will write the first key of a mapping item, the remaining keys are written at level+1
+func (w *yamlWriter) itemKey(level int, key, value string) {
+	w.indent(level)
+	fmt.Fprintf(&w.buf, "- %s: %s\n", key, strconv.Quote(value))
+}
+
+func (w *yamlWriter) list(level int, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	w.key(level, key)
+	for _, value := range values {
+		w.item(level+1, value)
+	}
+}
+
+// block will write multiline values as literal block scalars for readability
+// Note: YAML detects the indentation of a block from its first non-empty line, values which begin with
+// indented or blank lines get an explicit indentation indicator instead
+func (w *yamlWriter) block(level int, key, value string) {
+	if !strings.Contains(value, "\n") || strings.Contains(value, "\r") {
+		w.scalar(level, key, value)
+		return
+	}
+
+	var (
+		body      = strings.TrimRight(value, "\n")
+		trailing  = len(value) - len(body)
+		indicator string
+		chomping  string
+	)
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimLeft(line, " ") == "" {
+			if line != "" {
+				indicator = "2"
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") {
+			indicator = "2"
+		}
+
+		break
+	}
+
+	switch trailing {
+	case 0:
+		chomping = "-"
+	case 1:
+		chomping = ""
+	default:
+		chomping = "+"
+	}
+
+	w.indent(level)
+	fmt.Fprintf(&w.buf, "%s: |%s%s\n", key, indicator, chomping)
+	for _, line := range strings.Split(body, "\n") {
+		if line != "" {
+			w.indent(level + 1)
+			w.buf.WriteString(line)
+		}
+
+		w.buf.WriteString("\n")
+	}
+
+	// Keep the additional trailing newlines
+	for i := 1; i < trailing; i++ {
+		w.buf.WriteString("\n")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile is used when no profile is selected and the credentials are not set within the environment
+const defaultProfile = "default"
+
+// config represents the config file, e.g.
+//
+//	profiles:
+//	  default:
+//	    username: api-user
+//	    password: secret
+type config struct {
+	Profiles map[string]profile `yaml:"profiles"`
+}
+
+// profile represents a set of credentials within the config file
+type profile struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// configPath will return the path of the config file
+// Note: UPCLOUD_CONFIG overrides the default of <user config dir>/upcloud/config.yaml
+func configPath() (filename string, err error) {
+	if filename = os.Getenv("UPCLOUD_CONFIG"); filename != "" {
+		return
+	}
+
+	var dir string
+	if dir, err = os.UserConfigDir(); err != nil {
+		return
+	}
+
+	filename = filepath.Join(dir, "upcloud", "config.yaml")
+	return
+}
+
+// loadProfile will return the credentials of the provided profile
+// Note: When no profile is provided, UPCLOUD_PROFILE is used, then UPCLOUD_USERNAME and UPCLOUD_PASSWORD, then the default profile
+func loadProfile(name string) (p profile, err error) {
+	if name == "" {
+		name = os.Getenv("UPCLOUD_PROFILE")
+	}
+
+	if name == "" {
+		p.Username = os.Getenv("UPCLOUD_USERNAME")
+		p.Password = os.Getenv("UPCLOUD_PASSWORD")
+		if p.Username != "" && p.Password != "" {
+			return
+		}
+
+		name = defaultProfile
+	}
+
+	var filename string
+	if filename, err = configPath(); err != nil {
+		return
+	}
+
+	var bs []byte
+	if bs, err = ioutil.ReadFile(filename); err != nil {
+		err = fmt.Errorf("error reading profile \"%s\": %v", name, err)
+		return
+	}
+
+	var cfg config
+	if err = yaml.Unmarshal(bs, &cfg); err != nil {
+		err = fmt.Errorf("error parsing %s: %v", filename, err)
+		return
+	}
+
+	var ok bool
+	if p, ok = cfg.Profiles[name]; !ok {
+		err = fmt.Errorf("profile \"%s\" not found within %s", name, filename)
+		return
+	}
+
+	if p.Username == "" || p.Password == "" {
+		err = fmt.Errorf("profile \"%s\" requires a username and password", name)
+	}
+
+	return
+}
+
+func newClient(name string) (u upcloud.Client, err error) {
+	var p profile
+	if p, err = loadProfile(name); err != nil {
+		return
+	}
+
+	return upcloud.New(p.Username, p.Password)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/inventory"
+)
+
+// inventory will export the servers as an Ansible dynamic inventory or Prometheus file_sd targets
+// Note: In watch mode the file is rewritten whenever the servers change, until interrupted
+func (c *cli) inventory(args []string) (err error) {
+	var name string
+	if name, args, err = subcommand(args, "ansible", "prometheus"); err != nil {
+		return
+	}
+
+	var (
+		private  bool
+		filename string
+		watch    time.Duration
+		port     int
+		list     bool
+		host     string
+	)
+
+	var fs = c.flags("inventory " + name)
+	fs.BoolVar(&private, "private", false, "use the private addresses of servers instead of the public ones")
+	fs.StringVar(&filename, "file", "", "file to write to instead of stdout")
+	fs.DurationVar(&watch, "watch", 0, "interval to refresh the file at, requires --file")
+	if name == "prometheus" {
+		fs.IntVar(&port, "port", 9100, "port of the targets")
+	} else {
+		// Flags Ansible calls inventory scripts with
+		fs.BoolVar(&list, "list", true, "list the inventory")
+		fs.StringVar(&host, "host", "", "list the variables of a single host")
+	}
+
+	var positional []string
+	if positional, err = parse(fs, args); err != nil {
+		return
+	}
+
+	switch {
+	case len(positional) > 0:
+		return fmt.Errorf("unexpected argument \"%s\"", positional[0])
+	case watch > 0 && filename == "":
+		return errors.New("--watch requires --file")
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var render = func() (bs []byte, err error) {
+		var hosts []inventory.Host
+		if hosts, err = inventory.Collect(c.ctx, u); err != nil {
+			return
+		}
+
+		var value interface{}
+		switch {
+		case name == "prometheus":
+			value = inventory.NewPrometheus(hosts, port, private)
+		case host != "":
+			var vars = inventory.NewAnsible(hosts, private).HostVars[host]
+			if vars == nil {
+				vars = map[string]interface{}{}
+			}
+
+			value = vars
+		default:
+			value = inventory.NewAnsible(hosts, private)
+		}
+
+		if bs, err = json.MarshalIndent(value, "", "  "); err != nil {
+			return
+		}
+
+		bs = append(bs, '\n')
+		return
+	}
+
+	var bs []byte
+	if bs, err = render(); err != nil {
+		return
+	}
+
+	if err = c.writeOutput(filename, bs); err != nil || watch <= 0 {
+		return
+	}
+
+	var ticker = time.NewTicker(watch)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.ctx.Done():
+			// Interrupting is the expected way to stop watching
+			return nil
+		}
+
+		var next []byte
+		if next, err = render(); err != nil {
+			if c.ctx.Err() != nil {
+				return nil
+			}
+
+			// Keep the last inventory on transient errors, the next refresh may succeed
+			fmt.Fprintf(c.errOut, "error refreshing inventory: %v\n", err)
+			continue
+		}
+
+		if bytes.Equal(next, bs) {
+			continue
+		}
+
+		if err = c.writeOutput(filename, next); err != nil {
+			return
+		}
+
+		bs = next
+	}
+}
+
+// writeOutput will write to the file, or to stdout when no file is provided
+// Note: Files are replaced atomically, so readers never observe partial writes
+func (c *cli) writeOutput(filename string, bs []byte) (err error) {
+	if filename == "" {
+		_, err = c.out.Write(bs)
+		return
+	}
+
+	var f *os.File
+	if f, err = ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)); err != nil {
+		return
+	}
+
+	defer os.Remove(f.Name())
+	if err = f.Chmod(0644); err != nil {
+		f.Close()
+		return
+	}
+
+	if _, err = f.Write(bs); err != nil {
+		f.Close()
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(f.Name(), filename)
+}
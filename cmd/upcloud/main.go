@@ -0,0 +1,247 @@
+// Command upcloud manages UpCloud resources from the command line
+//
+// Usage:
+//
+//	upcloud [--profile name] [--output table|json|yaml] <command> [arguments]
+//
+// Commands:
+//
+//	account
+//	zones
+//	plans
+//	server list|show|create|start|stop|restart|delete
+//	storage list|create|delete
+//	inventory ansible|prometheus
+//	reconcile
+//
+// Credentials are read from the profile within the config file (see configPath),
+// or from UPCLOUD_USERNAME and UPCLOUD_PASSWORD when no profile is selected.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+const usage = `usage: upcloud [--profile name] [--output table|json|yaml] <command> [arguments]
+
+commands:
+  account                                  show the current account
+  zones                                    list zones
+  plans                                    list plans
+  server list                              list servers
+  server show <uuid>                       show server details
+  server create --zone --hostname [...]    create a server
+  server start|stop|restart <uuid>         change the state of a server
+  server delete <uuid> [--stop]            delete a server
+  storage list [--type private]            list storages
+  storage create --zone --size --title     create a storage
+  storage delete <uuid>                    delete a storage
+  inventory ansible|prometheus [--watch]   export servers for Ansible or Prometheus file_sd
+  reconcile <spec.yaml> [--dry-run|--yes]  apply a declarative server spec
+
+Run "upcloud <command> --help" for the flags of a command.
+`
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	var c cli
+	c.ctx = ctx
+	c.out = os.Stdout
+	c.errOut = os.Stderr
+	c.newClient = newClient
+	if err := c.run(os.Args[1:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "upcloud:", err)
+		}
+
+		cancel()
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned when the command line is invalid
+var errUsage = errors.New("invalid usage, run \"upcloud help\" for usage")
+
+// cli holds the state of a single invocation
+type cli struct {
+	// Canceled on interrupt, long running commands stop once canceled
+	ctx    context.Context
+	out    io.Writer
+	errOut io.Writer
+
+	newClient func(profile string) (upcloud.Client, error)
+	client    upcloud.Client
+
+	// Global options, every command accepts them as well
+	profile string
+	output  string
+}
+
+func (c *cli) run(args []string) (err error) {
+	var fs = c.flags("upcloud")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	if args = fs.Args(); len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "account":
+		return c.account(args[1:])
+	case "zones":
+		return c.zones(args[1:])
+	case "plans":
+		return c.plans(args[1:])
+	case "server":
+		return c.server(args[1:])
+	case "storage":
+		return c.storage(args[1:])
+	case "inventory":
+		return c.inventory(args[1:])
+	case "reconcile":
+		return c.reconcile(args[1:])
+	case "help":
+		fmt.Fprint(c.out, usage)
+		return
+	default:
+		return fmt.Errorf("unknown command \"%s\", run \"upcloud help\" for usage", args[0])
+	}
+}
+
+// flags will return a new flag set with the global options registered
+func (c *cli) flags(name string) *flag.FlagSet {
+	var fs = flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.StringVar(&c.profile, "profile", c.profile, "credentials profile within the config file")
+	fs.Var((*outputFlag)(&c.output), "output", "output format: table, json or yaml")
+	return fs
+}
+
+// connect will return the client of the selected profile
+func (c *cli) connect() (u upcloud.Client, err error) {
+	if c.client == nil {
+		if c.client, err = c.newClient(c.profile); err != nil {
+			return
+		}
+	}
+
+	return c.client, nil
+}
+
+// parse will parse the flags, allowing flags after positional arguments (e.g. "show <uuid> --output json")
+func parse(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return
+		}
+
+		if args = fs.Args(); len(args) == 0 {
+			return
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// subcommand will return the subcommand and its arguments
+func subcommand(args []string, names ...string) (name string, rest []string, err error) {
+	if len(args) == 0 {
+		err = fmt.Errorf("missing subcommand, expected one of %s", strings.Join(names, ", "))
+		return
+	}
+
+	for _, candidate := range names {
+		if args[0] == candidate {
+			return args[0], args[1:], nil
+		}
+	}
+
+	err = fmt.Errorf("unknown subcommand \"%s\", expected one of %s", args[0], strings.Join(names, ", "))
+	return
+}
+
+func (c *cli) account(args []string) (err error) {
+	if _, err = parse(c.flags("account"), args); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var a *upcloud.Account
+	if a, err = u.GetAccount(); err != nil {
+		return
+	}
+
+	return c.print(a, []string{"USERNAME", "CREDITS"}, [][]string{{a.Username, a.Credits.String()}})
+}
+
+func (c *cli) zones(args []string) (err error) {
+	if _, err = parse(c.flags("zones"), args); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var zones *[]upcloud.Zone
+	if zones, err = u.GetZones(); err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, z := range *zones {
+		rows = append(rows, []string{z.ID, z.Description, z.Public.String()})
+	}
+
+	return c.print(zones, []string{"ID", "DESCRIPTION", "PUBLIC"}, rows)
+}
+
+func (c *cli) plans(args []string) (err error) {
+	if _, err = parse(c.flags("plans"), args); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var plans *[]upcloud.Plan
+	if plans, err = u.GetPlans(); err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, p := range *plans {
+		rows = append(rows, []string{p.Name, fmt.Sprint(p.CoreNumber), fmt.Sprint(p.MemoryAmount), fmt.Sprint(p.StorageSize), p.StorageTier.String()})
+	}
+
+	return c.print(plans, []string{"NAME", "CORES", "MEMORY (MB)", "STORAGE (GB)", "TIER"}, rows)
+}
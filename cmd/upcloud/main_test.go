@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func TestCLI_Server(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+	srv.SetTransitionDelay(10 * time.Millisecond)
+
+	var out bytes.Buffer
+	var sd upcloud.ServerDetails
+	if err = run(srv, &out, "server", "create", "--zone", "fi-hel1", "--hostname", "web", "--disk-size", "30", "--disk-tier", "ssd"); err == nil {
+		t.Fatal("expected error with an invalid disk tier")
+	}
+
+	if err = run(srv, &out, "--output", "json", "server", "create", "--zone", "fi-hel1", "--hostname", "web", "--disk-size", "30", "--disk-tier", "hdd", "--tag", "prod", "--wait"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = json.Unmarshal(out.Bytes(), &sd); err != nil {
+		t.Fatal(err)
+	}
+
+	if sd.State != upcloud.ServerStateStarted {
+		t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", upcloud.ServerStateStarted, sd.State)
+	}
+
+	if sd.Password == "" {
+		t.Fatal("expected the password of the created server")
+	}
+
+	var devices = sd.StorageDevices.List()
+	if len(devices) != 1 || devices[0].StorageSize != 30 || devices[0].Tier != upcloud.StorageTierHDD {
+		t.Fatalf("invalid storage devices, expected a single clone of %d GB and received %+v", 30, devices)
+	}
+
+	out.Reset()
+	if err = run(srv, &out, "server", "restart", sd.UUID, "--wait"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), string(upcloud.ServerStateStarted)) {
+		t.Fatalf("invalid output, expected the started state and received %s", out.String())
+	}
+
+	out.Reset()
+	if err = run(srv, &out, "server", "list", "--output", "yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "hostname: web") {
+		t.Fatalf("invalid output, expected the server in yaml and received %s", out.String())
+	}
+
+	if err = run(srv, &out, "server", "delete", sd.UUID); err == nil {
+		t.Fatal("expected error deleting a started server")
+	}
+
+	if err = run(srv, &out, "server", "delete", sd.UUID, "--backups", "delete"); err == nil {
+		t.Fatal("expected error deleting backups without --storages")
+	}
+
+	if err = run(srv, &out, "server", "delete", sd.UUID, "--storages", "--backups", "delete", "--stop"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Servers()); n != 0 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 0, n)
+	}
+}
+
+func TestCLI_InvalidOutput(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	var out bytes.Buffer
+	for _, args := range [][]string{
+		{"--output", "xml", "server", "create", "--zone", "fi-hel1", "--hostname", "web"},
+		{"server", "create", "--zone", "fi-hel1", "--hostname", "web", "--output", "xml"},
+	} {
+		if err = run(srv, &out, args...); err == nil {
+			t.Fatalf("expected error with an invalid output format for %v", args)
+		}
+	}
+
+	if n := len(srv.Servers()); n != 0 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 0, n)
+	}
+}
+
+func TestCLI_ServerWaitCanceled(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+	srv.SetTransitionDelay(time.Hour)
+
+	// Cancel the context the way an interrupt would while waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(20*time.Millisecond, cancel)
+	defer timer.Stop()
+
+	var out bytes.Buffer
+	if err = runContext(ctx, srv, &out, "server", "create", "--zone", "fi-hel1", "--hostname", "web", "--wait"); err != context.Canceled {
+		t.Fatalf("invalid error, expected %v and received %v", context.Canceled, err)
+	}
+}
+
+func TestCLI_Storage(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	var out bytes.Buffer
+	if err = run(srv, &out, "storage", "create", "--zone", "de-fra1", "--size", "20", "--title", "data", "--tier", "maxiops"); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	var storages []upcloud.Storage
+	if err = run(srv, &out, "storage", "list", "--output", "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = json.Unmarshal(out.Bytes(), &storages); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(storages) != 1 || storages[0].Tier != upcloud.StorageTierMaxIOPS {
+		t.Fatalf("invalid storages, expected a single maxiops storage and received %+v", storages)
+	}
+
+	if err = run(srv, &out, "storage", "create", "--zone", "de-fra1", "--size", "20", "--title", "data", "--tier", "tape"); err == nil {
+		t.Fatal("expected error creating a storage with an invalid tier")
+	}
+
+	if err = run(srv, &out, "storage", "delete", storages[0].UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Storages()); n != 2 {
+		t.Fatalf("invalid number of storages, expected the %d seeded templates and received %d", 2, n)
+	}
+}
+
+func TestCLI_Reconcile(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	var dir string
+	if dir, err = ioutil.TempDir("", "upcloud"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var filename = filepath.Join(dir, "spec.yaml")
+	if err = ioutil.WriteFile(filename, []byte("servers:\n  - hostname: web\n    zone: fi-hel1\n    template: debian\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err = run(srv, &out, "reconcile", filename, "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "+ create web (fi-hel1)") {
+		t.Fatalf("invalid output, expected the creation of web and received %s", out.String())
+	}
+
+	if n := len(srv.Servers()); n != 0 {
+		t.Fatalf("invalid number of servers after a dry run, expected %d and received %d", 0, n)
+	}
+
+	if err = run(srv, &out, "reconcile", filename); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Servers()); n != 1 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 1, n)
+	}
+
+	// Pruning the server requires approval
+	if err = ioutil.WriteFile(filename, []byte("owner: web\nprune: true\nservers: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = srv.Client().TagServer(srv.Servers()[0].UUID, "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	if err = run(srv, &out, "reconcile", filename); err == nil {
+		t.Fatal("expected error applying a plan which deletes servers without --yes")
+	}
+
+	if !strings.Contains(out.String(), "- delete web (fi-hel1)") {
+		t.Fatalf("invalid output, expected the deletion of web and received %s", out.String())
+	}
+
+	if n := len(srv.Servers()); n != 1 {
+		t.Fatalf("invalid number of servers without approval, expected %d and received %d", 1, n)
+	}
+
+	if err = run(srv, &out, "reconcile", filename, "--yes", "--timeout", "10s"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Servers()); n != 0 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 0, n)
+	}
+}
+
+func TestCLI_Inventory(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	var out bytes.Buffer
+	if err = run(srv, &out, "server", "create", "--zone", "fi-hel1", "--hostname", "web", "--tag", "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	if err = run(srv, &out, "inventory", "ansible", "--list"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "\"tag_web\"") {
+		t.Fatalf("invalid output, expected the tag_web group and received %s", out.String())
+	}
+
+	var dir string
+	if dir, err = ioutil.TempDir("", "upcloud"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var filename = filepath.Join(dir, "targets.json")
+	if err = runContext(ctx, srv, &out, "inventory", "prometheus", "--file", filename, "--watch", "5ms", "--port", "9100"); err != nil {
+		t.Fatal(err)
+	}
+
+	var groups []struct {
+		Targets []string `json:"targets"`
+	}
+
+	var bs []byte
+	if bs, err = ioutil.ReadFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = json.Unmarshal(bs, &groups); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(groups) != 1 || !strings.HasSuffix(groups[0].Targets[0], ":9100") {
+		t.Fatalf("invalid target groups, received %s", bs)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	var err error
+	var dir string
+	if dir, err = ioutil.TempDir("", "upcloud"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var filename = filepath.Join(dir, "config.yaml")
+	if err = ioutil.WriteFile(filename, []byte("profiles:\n  staging:\n    username: staging-user\n    password: secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	setenv(t, "UPCLOUD_CONFIG", filename)
+	setenv(t, "UPCLOUD_PROFILE", "")
+	setenv(t, "UPCLOUD_USERNAME", "env-user")
+	setenv(t, "UPCLOUD_PASSWORD", "env-secret")
+
+	var p profile
+	if p, err = loadProfile(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Username != "env-user" {
+		t.Fatalf("invalid username, expected \"%s\" and received \"%s\"", "env-user", p.Username)
+	}
+
+	if p, err = loadProfile("staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Username != "staging-user" {
+		t.Fatalf("invalid username, expected \"%s\" and received \"%s\"", "staging-user", p.Username)
+	}
+
+	if _, err = loadProfile("production"); err == nil {
+		t.Fatal("expected error loading a missing profile")
+	}
+}
+
+func run(srv *upcloudtest.Server, out *bytes.Buffer, args ...string) error {
+	return runContext(context.Background(), srv, out, args...)
+}
+
+func runContext(ctx context.Context, srv *upcloudtest.Server, out *bytes.Buffer, args ...string) error {
+	var c cli
+	c.ctx = ctx
+	c.out = out
+	c.errOut = ioutil.Discard
+	c.newClient = func(profile string) (upcloud.Client, error) {
+		u := srv.Client()
+		u.SetPollInterval(5 * time.Millisecond)
+		return u, nil
+	}
+
+	return c.run(args)
+}
+
+func setenv(t *testing.T, key, value string) {
+	previous, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
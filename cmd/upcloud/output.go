@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// outputFlag is the --output flag, validated while parsing so invalid formats fail before any request
+type outputFlag string
+
+func (o *outputFlag) String() string {
+	return string(*o)
+}
+
+func (o *outputFlag) Set(value string) error {
+	switch value {
+	case outputTable, outputJSON, outputYAML:
+		*o = outputFlag(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid output format \"%s\", expected %s, %s or %s", value, outputTable, outputJSON, outputYAML)
+	}
+}
+
+// print will write the value in the selected output format
+// Note: The headers and rows are only used by the table format, json and yaml encode the value itself
+func (c *cli) print(value interface{}, headers []string, rows [][]string) (err error) {
+	switch c.output {
+	case "", outputTable:
+		return c.printTable(headers, rows)
+	case outputJSON:
+		return c.printJSON(value)
+	case outputYAML:
+		return c.printYAML(value)
+	default:
+		return fmt.Errorf("invalid output format \"%s\", expected %s, %s or %s", c.output, outputTable, outputJSON, outputYAML)
+	}
+}
+
+func (c *cli) printTable(headers []string, rows [][]string) (err error) {
+	var w = tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func (c *cli) printJSON(value interface{}) (err error) {
+	var bs []byte
+	if bs, err = json.MarshalIndent(value, "", "  "); err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(c.out, "%s\n", bs)
+	return
+}
+
+func (c *cli) printYAML(value interface{}) (err error) {
+	// Round trip through JSON so the field names and value formats match the json output
+	var bs []byte
+	if bs, err = json.Marshal(value); err != nil {
+		return
+	}
+
+	var generic interface{}
+	if err = json.Unmarshal(bs, &generic); err != nil {
+		return
+	}
+
+	if bs, err = yaml.Marshal(generic); err != nil {
+		return
+	}
+
+	_, err = c.out.Write(bs)
+	return
+}
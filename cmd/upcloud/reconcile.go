@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func (c *cli) reconcile(args []string) (err error) {
+	var dryRun, yes bool
+	var timeout time.Duration
+	var fs = c.flags("reconcile")
+	fs.BoolVar(&dryRun, "dry-run", false, "print the plan without applying it")
+	fs.BoolVar(&yes, "yes", false, "apply plans which delete servers")
+	fs.BoolVar(&yes, "auto-approve", false, "alias of --yes")
+	fs.DurationVar(&timeout, "timeout", defaultWaitTimeout, "limit of the reconciliation")
+
+	var positional []string
+	if positional, err = parse(fs, args); err != nil {
+		return
+	}
+
+	if len(positional) != 1 {
+		return fmt.Errorf("reconcile expects a single spec file argument")
+	}
+
+	var spec *upcloud.Spec
+	if spec, err = upcloud.ReadSpec(positional[0]); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+
+	var plan *upcloud.ReconcilePlan
+	switch {
+	case dryRun:
+		plan, err = u.PlanReconcile(ctx, spec)
+	case !yes:
+		// Plans which delete servers are only printed, unless approved with --yes
+		if plan, err = u.PlanReconcile(ctx, spec); err != nil {
+			return
+		}
+
+		if n := plan.Count(upcloud.ActionDelete); n > 0 {
+			err = fmt.Errorf("the plan deletes %d server(s), run again with --yes to apply it", n)
+			break
+		}
+
+		plan, err = u.Reconcile(ctx, spec)
+	default:
+		plan, err = u.Reconcile(ctx, spec)
+	}
+
+	if plan == nil {
+		return
+	}
+
+	// The plan is printed regardless of errors, so partially applied reconciliations are visible
+	var printErr error
+	switch c.output {
+	case "", outputTable:
+		_, printErr = fmt.Fprint(c.out, plan)
+	default:
+		printErr = c.print(plan, nil, nil)
+	}
+
+	if err == nil {
+		err = printErr
+	}
+
+	return
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+// defaultWaitTimeout is the default limit of --wait
+const defaultWaitTimeout = 10 * time.Minute
+
+var uuidExpr = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// waitFlags represents the flags of commands which can wait for a server state
+type waitFlags struct {
+	wait    bool
+	timeout time.Duration
+}
+
+func (w *waitFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&w.wait, "wait", false, "wait for the server to reach the resulting state")
+	fs.DurationVar(&w.timeout, "timeout", defaultWaitTimeout, "limit of --wait")
+}
+
+// stringsFlag is a repeatable string flag
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func (c *cli) server(args []string) (err error) {
+	var name string
+	if name, args, err = subcommand(args, "list", "show", "create", "start", "stop", "restart", "delete"); err != nil {
+		return
+	}
+
+	switch name {
+	case "list":
+		return c.serverList(args)
+	case "show":
+		return c.serverShow(args)
+	case "create":
+		return c.serverCreate(args)
+	case "start":
+		return c.serverStart(args)
+	case "stop":
+		return c.serverStop(args)
+	case "restart":
+		return c.serverRestart(args)
+	default:
+		return c.serverDelete(args)
+	}
+}
+
+func (c *cli) serverList(args []string) (err error) {
+	if _, err = parse(c.flags("server list"), args); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var servers *[]upcloud.Server
+	if servers, err = u.GetServers(); err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, s := range *servers {
+		rows = append(rows, []string{s.UUID, s.Hostname, s.Zone, s.Plan, s.State.String(), strings.Join(s.Tags.List(), ",")})
+	}
+
+	return c.print(servers, []string{"UUID", "HOSTNAME", "ZONE", "PLAN", "STATE", "TAGS"}, rows)
+}
+
+func (c *cli) serverShow(args []string) (err error) {
+	var uuid string
+	if uuid, err = c.parseUUID("server show", args, nil); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = u.GetServerDetails(uuid); err != nil {
+		return
+	}
+
+	return c.printServer(sd)
+}
+
+func (c *cli) serverCreate(args []string) (err error) {
+	var (
+		zone     string
+		hostname string
+		title    string
+		plan     string
+		template string
+		diskSize int
+		diskTier string
+		sshKey   string
+		userData string
+		tags     stringsFlag
+		w        waitFlags
+	)
+
+	var fs = c.flags("server create")
+	fs.StringVar(&zone, "zone", "", "zone of the server (required)")
+	fs.StringVar(&hostname, "hostname", "", "hostname of the server (required)")
+	fs.StringVar(&title, "title", "", "title of the server, defaults to the hostname")
+	fs.StringVar(&plan, "plan", "1xCPU-1GB", "plan of the server")
+	fs.StringVar(&template, "template", "ubuntu", "template UUID or OS family of the boot disk")
+	fs.IntVar(&diskSize, "disk-size", 0, "size of the boot disk in GB, defaults to the size of the plan")
+	fs.StringVar(&diskTier, "disk-tier", "", "tier of the boot disk")
+	fs.StringVar(&sshKey, "ssh-key", "", "authorized_keys file of the SSH keys to inject")
+	fs.StringVar(&userData, "user-data", "", "file of the user data script or cloud-config")
+	fs.Var(&tags, "tag", "tag of the server, may be repeated")
+	w.register(fs)
+
+	var positional []string
+	if positional, err = parse(fs, args); err != nil {
+		return
+	}
+
+	switch {
+	case len(positional) > 0:
+		return fmt.Errorf("unexpected argument \"%s\"", positional[0])
+	case zone == "":
+		return errors.New("--zone is required")
+	case hostname == "":
+		return errors.New("--hostname is required")
+	}
+
+	if tier := upcloud.StorageTier(diskTier); diskTier != "" && !tier.Valid() {
+		return fmt.Errorf("invalid storage tier \"%s\"", diskTier)
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var uuid string
+	if uuid, err = u.ResolveTemplate(template, zone); err != nil {
+		err = fmt.Errorf("error finding template \"%s\": %w", template, err)
+		return
+	}
+
+	var b = upcloud.NewServerBuilder(zone, hostname).
+		Title(title).
+		Plan(plan).
+		CloneTemplateWithTier(uuid, hostname+"-disk", diskSize, upcloud.StorageTier(diskTier)).
+		Tags(tags...)
+
+	if sshKey != "" {
+		b.AuthorizedKeys(sshKey)
+	}
+
+	if userData != "" {
+		var bs []byte
+		if bs, err = ioutil.ReadFile(userData); err != nil {
+			return
+		}
+
+		b.UserData(string(bs))
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = b.Build(); err != nil {
+		return
+	}
+
+	var created *upcloud.ServerDetails
+	if created, err = u.CreateServer(sd); err != nil {
+		return
+	}
+
+	if w.wait {
+		// The password is only returned on creation, retain it over the refreshed details
+		var password = created.Password
+		if created, err = c.waitFor(u, created.UUID, upcloud.ServerStateStarted, w.timeout); err != nil {
+			return
+		}
+
+		created.Password = password
+	}
+
+	return c.printServer(created)
+}
+
+func (c *cli) serverStart(args []string) (err error) {
+	var w waitFlags
+	var uuid string
+	if uuid, err = c.parseUUID("server start", args, w.register); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = u.StartServer(uuid, upcloud.StartServer{}); err != nil {
+		return
+	}
+
+	if w.wait {
+		if sd, err = c.waitFor(u, uuid, upcloud.ServerStateStarted, w.timeout); err != nil {
+			return
+		}
+	}
+
+	return c.printServer(sd)
+}
+
+func (c *cli) serverStop(args []string) (err error) {
+	var w waitFlags
+	var hard bool
+	var uuid string
+	if uuid, err = c.parseUUID("server stop", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&hard, "hard", false, "stop the server immediately instead of shutting it down")
+		w.register(fs)
+	}); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = u.StopServer(uuid, stopOptions(hard)); err != nil {
+		return
+	}
+
+	if w.wait {
+		if sd, err = c.waitFor(u, uuid, upcloud.ServerStateStopped, w.timeout); err != nil {
+			return
+		}
+	}
+
+	return c.printServer(sd)
+}
+
+// serverRestart will stop the server, wait for it to stop and start it again
+func (c *cli) serverRestart(args []string) (err error) {
+	var w waitFlags
+	var hard bool
+	var uuid string
+	if uuid, err = c.parseUUID("server restart", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&hard, "hard", false, "stop the server immediately instead of shutting it down")
+		w.register(fs)
+	}); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	if _, err = u.StopServer(uuid, stopOptions(hard)); err != nil {
+		return
+	}
+
+	// A server can only be started once stopped, so the timeout applies to the stop regardless of --wait
+	if _, err = c.waitFor(u, uuid, upcloud.ServerStateStopped, w.timeout); err != nil {
+		return
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = u.StartServer(uuid, upcloud.StartServer{}); err != nil {
+		return
+	}
+
+	if w.wait {
+		if sd, err = c.waitFor(u, uuid, upcloud.ServerStateStarted, w.timeout); err != nil {
+			return
+		}
+	}
+
+	return c.printServer(sd)
+}
+
+func (c *cli) serverDelete(args []string) (err error) {
+	var (
+		options upcloud.DeleteServer
+		keep    stringsFlag
+		backups string
+		hard    bool
+		timeout time.Duration
+	)
+
+	var uuid string
+	if uuid, err = c.parseUUID("server delete", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&options.Storages, "storages", false, "delete the attached storages as well")
+		fs.Var(&keep, "keep-storage", "UUID of an attached storage to keep with --storages (repeatable)")
+		fs.StringVar(&backups, "backups", "", "backups of deleted storages: keep, keep_latest or delete")
+		fs.BoolVar(&options.Stop, "stop", false, "stop the server first when started")
+		fs.BoolVar(&hard, "hard", false, "stop the server immediately with --stop instead of shutting it down")
+		fs.DurationVar(&options.StopTimeout, "stop-timeout", upcloud.DefaultStopTimeout, "time to shut down with --stop before the server is stopped forcibly")
+		fs.DurationVar(&timeout, "timeout", defaultWaitTimeout, "limit of --stop")
+	}); err != nil {
+		return
+	}
+
+	if hard {
+		options.StopType = upcloud.Hard
+	}
+
+	options.KeepStorages = keep
+	options.Backups = upcloud.BackupPolicy(backups)
+	if (len(keep) > 0 || backups != "") && !options.Storages {
+		err = fmt.Errorf("--keep-storage and --backups require --storages")
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+
+	if err = u.DeleteServerWithOptions(ctx, uuid, options); errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("server %s did not stop within %v", uuid, timeout)
+	}
+
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.errOut, "deleted server %s\n", uuid)
+	return
+}
+
+// parseUUID will parse the flags of a command which expects a single UUID argument
+func (c *cli) parseUUID(name string, args []string, register func(*flag.FlagSet)) (uuid string, err error) {
+	var fs = c.flags(name)
+	if register != nil {
+		register(fs)
+	}
+
+	var positional []string
+	if positional, err = parse(fs, args); err != nil {
+		return
+	}
+
+	if len(positional) != 1 {
+		err = fmt.Errorf("%s expects a single UUID argument", name)
+		return
+	}
+
+	uuid = positional[0]
+	return
+}
+
+// waitFor will wait for the server to reach the provided state within the timeout
+func (c *cli) waitFor(u upcloud.Client, uuid string, state upcloud.ServerState, timeout time.Duration) (sd *upcloud.ServerDetails, err error) {
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+
+	if sd, err = u.WaitForState(ctx, uuid, state); errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("server %s did not reach the %s state within %v", uuid, state, timeout)
+	}
+
+	return
+}
+
+func (c *cli) printServer(sd *upcloud.ServerDetails) (err error) {
+	var rows = [][]string{
+		{"UUID", sd.UUID},
+		{"HOSTNAME", sd.Hostname},
+		{"TITLE", sd.Title},
+		{"ZONE", sd.Zone},
+		{"PLAN", sd.Plan},
+		{"STATE", sd.State.String()},
+		{"TAGS", strings.Join(sd.Tags.List(), ",")},
+	}
+
+	for _, ip := range sd.IPAddresses.List() {
+		rows = append(rows, []string{"IP", fmt.Sprintf("%s (%s %s)", ip.Address, ip.Access, ip.Family)})
+	}
+
+	for _, device := range sd.StorageDevices.List() {
+		rows = append(rows, []string{"STORAGE", fmt.Sprintf("%s %s (%d GB)", device.Storage, device.StorageTitle, device.StorageSize)})
+	}
+
+	if sd.Password != "" {
+		rows = append(rows, []string{"PASSWORD", sd.Password})
+	}
+
+	return c.print(sd, []string{"FIELD", "VALUE"}, rows)
+}
+
+func stopOptions(hard bool) (options upcloud.StopServer) {
+	options.StopType = string(upcloud.Soft)
+	if hard {
+		options.StopType = string(upcloud.Hard)
+	}
+
+	return
+}
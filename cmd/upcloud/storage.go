@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+// storageFilters are the filters of storage list by name
+var storageFilters = map[string]upcloud.RouteGetStorageFilter{
+	"all":      upcloud.All,
+	"public":   upcloud.Public,
+	"private":  upcloud.Private,
+	"normal":   upcloud.Normal,
+	"backup":   upcloud.Backup,
+	"cdrom":    upcloud.Cdrom,
+	"template": upcloud.Template,
+	"favorite": upcloud.Favorite,
+}
+
+func (c *cli) storage(args []string) (err error) {
+	var name string
+	if name, args, err = subcommand(args, "list", "create", "delete"); err != nil {
+		return
+	}
+
+	switch name {
+	case "list":
+		return c.storageList(args)
+	case "create":
+		return c.storageCreate(args)
+	default:
+		return c.storageDelete(args)
+	}
+}
+
+func (c *cli) storageList(args []string) (err error) {
+	var filterName string
+	var fs = c.flags("storage list")
+	fs.StringVar(&filterName, "type", "private", "storages to list: all, public, private, normal, backup, cdrom, template or favorite")
+	if _, err = parse(fs, args); err != nil {
+		return
+	}
+
+	filter, ok := storageFilters[filterName]
+	if !ok {
+		return fmt.Errorf("invalid storage type \"%s\"", filterName)
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var storages *[]upcloud.Storage
+	if storages, err = u.GetStorages(filter); err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, s := range *storages {
+		rows = append(rows, []string{s.UUID, s.Title, s.Zone, fmt.Sprint(s.Size), s.Tier.String(), string(s.Type), string(s.State)})
+	}
+
+	return c.print(storages, []string{"UUID", "TITLE", "ZONE", "SIZE (GB)", "TIER", "TYPE", "STATE"}, rows)
+}
+
+func (c *cli) storageCreate(args []string) (err error) {
+	var options upcloud.CreateStorage
+	var tier string
+	var fs = c.flags("storage create")
+	fs.StringVar(&options.Zone, "zone", "", "zone of the storage (required)")
+	fs.IntVar(&options.Size, "size", 0, "size of the storage in GB (required)")
+	fs.StringVar(&options.Title, "title", "", "title of the storage (required)")
+	fs.StringVar(&tier, "tier", "", "tier of the storage: "+strings.Join([]string{
+		upcloud.StorageTierHDD.String(),
+		upcloud.StorageTierMaxIOPS.String(),
+		upcloud.StorageTierStandard.String(),
+	}, ", "))
+
+	var positional []string
+	if positional, err = parse(fs, args); err != nil {
+		return
+	}
+
+	switch {
+	case len(positional) > 0:
+		return fmt.Errorf("unexpected argument \"%s\"", positional[0])
+	case options.Zone == "":
+		return errors.New("--zone is required")
+	case options.Size <= 0:
+		return errors.New("--size is required")
+	case options.Title == "":
+		return errors.New("--title is required")
+	}
+
+	if options.Tier = upcloud.StorageTier(tier); tier != "" && !options.Tier.Valid() {
+		return fmt.Errorf("invalid storage tier \"%s\"", tier)
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	var s *upcloud.Storage
+	if s, err = u.CreateStorage(options); err != nil {
+		return
+	}
+
+	var rows = [][]string{{s.UUID, s.Title, s.Zone, fmt.Sprint(s.Size), s.Tier.String(), string(s.Type), string(s.State)}}
+	return c.print(s, []string{"UUID", "TITLE", "ZONE", "SIZE (GB)", "TIER", "TYPE", "STATE"}, rows)
+}
+
+func (c *cli) storageDelete(args []string) (err error) {
+	var uuid string
+	if uuid, err = c.parseUUID("storage delete", args, nil); err != nil {
+		return
+	}
+
+	var u upcloud.Client
+	if u, err = c.connect(); err != nil {
+		return
+	}
+
+	if err = u.DeleteStorage(uuid); err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.errOut, "deleted storage %s\n", uuid)
+	return
+}
@@ -0,0 +1,136 @@
+package upcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/hatchify/requester"
+)
+
+// DefaultProtectionTag is the tag which protects servers from DeleteServerWithOptions
+const DefaultProtectionTag = "protected"
+
+// ErrServerProtected is returned when deleting a server carrying the protection tag
+var ErrServerProtected = errors.New("server is protected from deletion")
+
+// DeleteServer represents the options for deleting servers with DeleteServerWithOptions
+type DeleteServer struct {
+	// Delete the disks attached to the server
+	Storages bool
+	// UUIDs of attached storages to keep when deleting storages
+	KeepStorages []string
+	// What happens to the backups of deleted storages, UpCloud keeps them by default
+	Backups BackupPolicy
+	// Stop the server first when started and wait for it to stop
+	// Note: The context bounds how long to wait
+	Stop bool
+	// Type of the stop, a soft stop is used when empty
+	StopType ServerStopType
+	// Time given to a soft stop before UpCloud stops the server forcibly, DefaultStopTimeout is used when not set
+	StopTimeout time.Duration
+	// Tag which protects servers from deletion, DefaultProtectionTag is used when empty
+	// Note: Remove the tag from the server to delete it
+	ProtectionTag string
+}
+
+// DeleteServerWithOptions deletes an already existing server along with the selected storages
+// Note: ErrServerProtected is returned for servers carrying the protection tag. When storages are kept,
+// the server is deleted first and the remaining disks are deleted one by one afterwards
+func (u *UpCloud) DeleteServerWithOptions(ctx context.Context, uuid string, options DeleteServer) (err error) {
+	if options.Backups != "" && !options.Backups.Valid() {
+		return fmt.Errorf("invalid backup policy \"%s\"", options.Backups)
+	}
+
+	if options.StopType != "" && options.StopType != Soft && options.StopType != Hard {
+		return fmt.Errorf("invalid stop type \"%s\"", options.StopType)
+	}
+
+	if options.ProtectionTag == "" {
+		options.ProtectionTag = DefaultProtectionTag
+	}
+
+	var sd *ServerDetails
+	if sd, err = u.GetServerDetails(uuid); err != nil {
+		return
+	}
+
+	if hasTag(sd.Tags.List(), options.ProtectionTag) {
+		return ErrServerProtected
+	}
+
+	var disks []string
+	var keep = make(map[string]bool, len(options.KeepStorages))
+	for _, device := range sd.StorageDevices.List() {
+		if device.Type == "disk" {
+			disks = append(disks, device.Storage)
+		}
+	}
+
+	for _, storage := range options.KeepStorages {
+		if !containsString(disks, storage) {
+			return fmt.Errorf("storage %s to keep is not attached to server %s", storage, uuid)
+		}
+
+		keep[storage] = true
+	}
+
+	if options.Stop && sd.State != ServerStateStopped {
+		if err = u.stopAndWait(ctx, uuid, options.StopType, options.StopTimeout); err != nil {
+			return
+		}
+	}
+
+	if !options.Storages || len(keep) == 0 {
+		return u.deleteServer(uuid, options.Storages, options.Backups)
+	}
+
+	if err = u.deleteServer(uuid, false, ""); err != nil {
+		return
+	}
+
+	for _, storage := range disks {
+		if keep[storage] {
+			continue
+		}
+
+		if err = u.deleteStorage(storage, options.Backups); err != nil {
+			err = fmt.Errorf("server %s was deleted, but deleting storage %s failed: %w", uuid, storage, err)
+			return
+		}
+	}
+
+	return
+}
+
+func (u *UpCloud) deleteServer(uuid string, deleteStorage bool, backups BackupPolicy) (err error) {
+	var params []requester.QueryParam
+
+	// Parameters to delete the storages associated with the server, along with their backups
+	if deleteStorage {
+		params = append(params, requester.QueryParam{Key: "storages", Val: "1"})
+		if backups != "" {
+			params = append(params, requester.QueryParam{Key: "backups", Val: backups.String()})
+		}
+	}
+
+	var opts requester.Opts
+	if len(params) > 0 {
+		opts = requester.Opts{requester.NewQuery(params...)}
+	}
+
+	// Make request to delete the server
+	return u.request("DeleteServer", "DELETE", path.Join(RouteServer, uuid), opts, nil, nil)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+package upcloud_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func TestUpCloud_DeleteServerWithOptions(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("us-chi1", "sdk-test-machine").
+		Plan("1xCPU-1GB").
+		CloneTemplate(upcloudtest.TemplateUbuntu, "sdk-test-disk", 25).
+		AddDisk("sdk-test-data", 10, upcloud.StorageTierMaxIOPS).
+		Tags("Protected").
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var s *upcloud.ServerDetails
+	if s, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{Stop: true}); err != upcloud.ErrServerProtected {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrServerProtected, err)
+	}
+
+	devices := s.StorageDevices.List()
+	system, data := devices[0].Storage, devices[1].Storage
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{ProtectionTag: "production", Storages: true, KeepStorages: []string{"unknown"}}); err == nil {
+		t.Fatal("expected error keeping a storage which is not attached")
+	}
+
+	// Backups of the system disk, only the latest one should remain
+	now := time.Now()
+	srv.AddStorage(upcloud.Storage{Type: upcloud.StorageTypeBackup, Origin: system, Created: now.Add(-time.Hour), Zone: "us-chi1"})
+	latest := srv.AddStorage(upcloud.Storage{Type: upcloud.StorageTypeBackup, Origin: system, Created: now, Zone: "us-chi1"})
+
+	options := upcloud.DeleteServer{
+		Storages:      true,
+		KeepStorages:  []string{data},
+		Backups:       upcloud.BackupsKeepLatest,
+		Stop:          true,
+		ProtectionTag: "production",
+	}
+
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(srv.Servers()); n != 0 {
+		t.Fatalf("invalid number of servers, expected %d and received %d", 0, n)
+	}
+
+	var remaining = make(map[string]bool)
+	for _, storage := range srv.Storages() {
+		if storage.Type != upcloud.StorageTypeTemplate {
+			remaining[storage.UUID] = true
+		}
+	}
+
+	if len(remaining) != 2 || !remaining[data] || !remaining[latest.UUID] {
+		t.Fatalf("invalid storages, expected %s and %s to remain and received %v", data, latest.UUID, remaining)
+	}
+}
+
+func TestUpCloud_DeleteServerWithOptions_StorageError(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("us-chi1", "sdk-test-machine").
+		CloneTemplate(upcloudtest.TemplateUbuntu, "sdk-test-disk", 25).
+		AddDisk("sdk-test-data", 10, upcloud.StorageTierMaxIOPS).
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var s *upcloud.ServerDetails
+	if s, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Fail(upcloudtest.Failure{Method: "DELETE", Path: "storage/", StatusCode: 409, ErrorCode: "STORAGE_IN_USE", ErrorMessage: "The storage is in use."})
+
+	var data = s.StorageDevices.List()[1].Storage
+	err = u.DeleteServerWithOptions(context.Background(), s.UUID, upcloud.DeleteServer{Storages: true, KeepStorages: []string{data}, Stop: true})
+
+	var apiErr *upcloud.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "STORAGE_IN_USE" {
+		t.Fatalf("invalid error, expected %s and received %v", "STORAGE_IN_USE", err)
+	}
+}
+
+func TestUpCloud_DeleteServerWithOptions_Backups(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	s := createServer(t, u)
+	srv.AddStorage(upcloud.Storage{Type: upcloud.StorageTypeBackup, Origin: s.StorageDevices.List()[0].Storage, Zone: "us-chi1"})
+
+	ctx := context.Background()
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{Storages: true, Backups: "sometimes"}); err == nil {
+		t.Fatal("expected error with an invalid backup policy")
+	}
+
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{Stop: true, StopType: "gentle"}); err == nil {
+		t.Fatal("expected error with an invalid stop type")
+	}
+
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{Storages: true, Backups: upcloud.BackupsDelete}); err == nil {
+		t.Fatal("expected error deleting a server which is not stopped")
+	}
+
+	if _, err = u.WaitForState(ctx, s.UUID, upcloud.ServerStateStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = u.DeleteServerWithOptions(ctx, s.UUID, upcloud.DeleteServer{Storages: true, Backups: upcloud.BackupsDelete, Stop: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, storage := range srv.Storages() {
+		if storage.Type != upcloud.StorageTypeTemplate {
+			t.Fatalf("invalid storages, expected none besides templates and received %s", storage.UUID)
+		}
+	}
+}
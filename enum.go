@@ -0,0 +1,288 @@
+package upcloud
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ServerState represents the state of an UpCloud server
+type ServerState string
+
+const (
+	ServerStateStarted     ServerState = "started"
+	ServerStateStopped     ServerState = "stopped"
+	ServerStateMaintenance ServerState = "maintenance"
+	ServerStateError       ServerState = "error"
+)
+
+var serverStates = []string{
+	string(ServerStateStarted),
+	string(ServerStateStopped),
+	string(ServerStateMaintenance),
+	string(ServerStateError),
+}
+
+// String will return the string representation of the server state
+func (s ServerState) String() string {
+	return string(s)
+}
+
+// Valid will return whether or not the server state is known to the SDK
+func (s ServerState) Valid() bool {
+	return isKnown(string(s), serverStates)
+}
+
+// UnmarshalJSON will unmarshal a server state
+// Note: Unknown states are preserved as-is
+func (s *ServerState) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, serverStates); err != nil {
+		return
+	}
+
+	*s = ServerState(str)
+	return
+}
+
+// StorageState represents the state of an UpCloud storage
+type StorageState string
+
+const (
+	StorageStateOnline      StorageState = "online"
+	StorageStateMaintenance StorageState = "maintenance"
+	StorageStateCloning     StorageState = "cloning"
+	StorageStateBackuping   StorageState = "backuping"
+	StorageStateSyncing     StorageState = "syncing"
+	StorageStateError       StorageState = "error"
+)
+
+var storageStates = []string{
+	string(StorageStateOnline),
+	string(StorageStateMaintenance),
+	string(StorageStateCloning),
+	string(StorageStateBackuping),
+	string(StorageStateSyncing),
+	string(StorageStateError),
+}
+
+// String will return the string representation of the storage state
+func (s StorageState) String() string {
+	return string(s)
+}
+
+// Valid will return whether or not the storage state is known to the SDK
+func (s StorageState) Valid() bool {
+	return isKnown(string(s), storageStates)
+}
+
+// UnmarshalJSON will unmarshal a storage state
+// Note: Unknown states are preserved as-is
+func (s *StorageState) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, storageStates); err != nil {
+		return
+	}
+
+	*s = StorageState(str)
+	return
+}
+
+// StorageType represents the type of an UpCloud storage
+type StorageType string
+
+const (
+	StorageTypeNormal   StorageType = "normal"
+	StorageTypeBackup   StorageType = "backup"
+	StorageTypeCdrom    StorageType = "cdrom"
+	StorageTypeTemplate StorageType = "template"
+)
+
+var storageTypes = []string{
+	string(StorageTypeNormal),
+	string(StorageTypeBackup),
+	string(StorageTypeCdrom),
+	string(StorageTypeTemplate),
+}
+
+// String will return the string representation of the storage type
+func (s StorageType) String() string {
+	return string(s)
+}
+
+// Valid will return whether or not the storage type is known to the SDK
+func (s StorageType) Valid() bool {
+	return isKnown(string(s), storageTypes)
+}
+
+// UnmarshalJSON will unmarshal a storage type
+// Note: Unknown types are preserved as-is
+func (s *StorageType) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, storageTypes); err != nil {
+		return
+	}
+
+	*s = StorageType(str)
+	return
+}
+
+// StorageTier represents the tier of an UpCloud storage
+type StorageTier string
+
+const (
+	StorageTierHDD      StorageTier = "hdd"
+	StorageTierMaxIOPS  StorageTier = "maxiops"
+	StorageTierStandard StorageTier = "standard"
+)
+
+// DefaultStorageTier is the tier UpCloud applies to server disks created without one
+const DefaultStorageTier = StorageTierMaxIOPS
+
+var storageTiers = []string{
+	string(StorageTierHDD),
+	string(StorageTierMaxIOPS),
+	string(StorageTierStandard),
+}
+
+// String will return the string representation of the storage tier
+func (s StorageTier) String() string {
+	return string(s)
+}
+
+// Valid will return whether or not the storage tier is known to the SDK
+func (s StorageTier) Valid() bool {
+	return isKnown(string(s), storageTiers)
+}
+
+// UnmarshalJSON will unmarshal a storage tier
+// Note: Unknown tiers are preserved as-is
+func (s *StorageTier) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, storageTiers); err != nil {
+		return
+	}
+
+	*s = StorageTier(str)
+	return
+}
+
+// StorageAccess represents the access type of an UpCloud storage
+type StorageAccess string
+
+const (
+	StorageAccessPublic  StorageAccess = "public"
+	StorageAccessPrivate StorageAccess = "private"
+)
+
+var storageAccesses = []string{
+	string(StorageAccessPublic),
+	string(StorageAccessPrivate),
+}
+
+// String will return the string representation of the storage access
+func (s StorageAccess) String() string {
+	return string(s)
+}
+
+// Valid will return whether or not the storage access is known to the SDK
+func (s StorageAccess) Valid() bool {
+	return isKnown(string(s), storageAccesses)
+}
+
+// UnmarshalJSON will unmarshal a storage access
+// Note: Unknown access types are preserved as-is
+func (s *StorageAccess) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, storageAccesses); err != nil {
+		return
+	}
+
+	*s = StorageAccess(str)
+	return
+}
+
+// IPAddressFamily represents the family of an UpCloud IP address
+type IPAddressFamily string
+
+const (
+	IPv4 IPAddressFamily = "IPv4"
+	IPv6 IPAddressFamily = "IPv6"
+)
+
+var ipAddressFamilies = []string{
+	string(IPv4),
+	string(IPv6),
+}
+
+// String will return the string representation of the IP address family
+func (i IPAddressFamily) String() string {
+	return string(i)
+}
+
+// Valid will return whether or not the IP address family is known to the SDK
+func (i IPAddressFamily) Valid() bool {
+	return isKnown(string(i), ipAddressFamilies)
+}
+
+// UnmarshalJSON will unmarshal an IP address family
+// Note: Unknown families are preserved as-is
+func (i *IPAddressFamily) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = unmarshalEnum(bs, ipAddressFamilies); err != nil {
+		return
+	}
+
+	*i = IPAddressFamily(str)
+	return
+}
+
+// BackupPolicy represents what happens to the backups of deleted storages
+type BackupPolicy string
+
+const (
+	BackupsKeep       BackupPolicy = "keep"
+	BackupsKeepLatest BackupPolicy = "keep_latest"
+	BackupsDelete     BackupPolicy = "delete"
+)
+
+var backupPolicies = []string{
+	string(BackupsKeep),
+	string(BackupsKeepLatest),
+	string(BackupsDelete),
+}
+
+// String will return the string representation of the backup policy
+func (b BackupPolicy) String() string {
+	return string(b)
+}
+
+// Valid will return whether or not the backup policy is known to the SDK
+func (b BackupPolicy) Valid() bool {
+	return isKnown(string(b), backupPolicies)
+}
+
+// unmarshalEnum will unmarshal a JSON string and normalize the casing of known values
+func unmarshalEnum(bs []byte, known []string) (str string, err error) {
+	if err = json.Unmarshal(bs, &str); err != nil {
+		return
+	}
+
+	for _, k := range known {
+		if strings.EqualFold(str, k) {
+			str = k
+			break
+		}
+	}
+
+	return
+}
+
+func isKnown(str string, known []string) bool {
+	for _, k := range known {
+		if str == k {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,346 @@
+package upcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AccountStateVersion is the version of the account state format written by ExportAccountState
+// Note: The version is incremented on incompatible changes, ParseAccountState rejects newer versions
+const AccountStateVersion = 1
+
+// ErrUnsupportedVersion is returned when parsing an account state of a newer version
+var ErrUnsupportedVersion = errors.New("unsupported account state version")
+
+const (
+	// ChangeAdded is a resource only present within the later state
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved is a resource only present within the earlier state
+	ChangeRemoved ChangeType = "removed"
+	// ChangeChanged is a resource with different fields between the states
+	ChangeChanged ChangeType = "changed"
+)
+
+const (
+	resourceServer    = "server"
+	resourceStorage   = "storage"
+	resourceNetwork   = "network"
+	resourceIPAddress = "ip_address"
+	resourceTag       = "tag"
+)
+
+// ChangeType represents the type of a state change
+type ChangeType string
+
+// AccountState represents every resource of an account at a point in time, for audits and disaster recovery
+// Note: Resources are sorted so exports of the same state are identical
+type AccountState struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Username   string    `json:"username"`
+
+	Servers     []ServerExport    `json:"servers"`
+	Storages    []Storage         `json:"storages"`
+	Networks    []Network         `json:"networks"`
+	IPAddresses []IPAddressExport `json:"ip_addresses"`
+	Tags        []TagExport       `json:"tags"`
+}
+
+// ServerExport represents the details and firewall rules of a server
+type ServerExport struct {
+	ServerDetails
+	FirewallRules []FirewallRule `json:"firewall_rules"`
+}
+
+// IPAddressExport represents an IP address along with the server it is assigned to
+type IPAddressExport struct {
+	IPAddress
+	Server string `json:"server"`
+}
+
+// TagExport represents a tag along with the servers carrying it
+type TagExport struct {
+	Name    string   `json:"name"`
+	Servers []string `json:"servers"`
+}
+
+// StateChange represents the difference of a resource between two account states
+type StateChange struct {
+	Type ChangeType `json:"type"`
+	// Resource type: server, storage, network, ip_address or tag
+	Resource string `json:"resource"`
+	ID       string `json:"id"`
+	// Hostname, title or name of the resource
+	Name string `json:"name,omitempty"`
+	// Changed fields (e.g. "plan", "firewall_rules"), only set for changed resources
+	Fields []string `json:"fields,omitempty"`
+}
+
+// String will return the change as a human readable line
+func (c StateChange) String() string {
+	var symbol = map[ChangeType]string{ChangeAdded: "+", ChangeRemoved: "-", ChangeChanged: "~"}[c.Type]
+	var out = fmt.Sprintf("%s %s %s", symbol, c.Resource, c.ID)
+	if c.Name != "" && c.Name != c.ID {
+		out += " (" + c.Name + ")"
+	}
+
+	if len(c.Fields) > 0 {
+		out += ": " + strings.Join(c.Fields, ", ")
+	}
+
+	return out
+}
+
+// ExportAccountState will collect the servers (with details and firewall rules), private storages,
+// networks, IP addresses and tags of the account into a single document
+func (u *UpCloud) ExportAccountState(ctx context.Context) (s *AccountState, err error) {
+	var state AccountState
+	state.Version = AccountStateVersion
+	state.ExportedAt = time.Now().UTC()
+
+	var a *Account
+	if a, err = u.GetAccount(); err != nil {
+		return
+	}
+
+	state.Username = a.Username
+
+	var servers *[]Server
+	if servers, err = u.GetServers(); err != nil {
+		return
+	}
+
+	var tags = make(map[string][]string)
+	for _, server := range *servers {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var export ServerExport
+		var sd *ServerDetails
+		if sd, err = u.GetServerDetails(server.UUID); err != nil {
+			return
+		}
+
+		export.ServerDetails = *sd
+
+		var rules *[]FirewallRule
+		if rules, err = u.GetFirewallRules(server.UUID); err != nil {
+			return
+		}
+
+		export.FirewallRules = append([]FirewallRule{}, *rules...)
+		state.Servers = append(state.Servers, export)
+
+		for _, ip := range sd.IPAddresses.List() {
+			state.IPAddresses = append(state.IPAddresses, IPAddressExport{IPAddress: ip, Server: sd.UUID})
+		}
+
+		for _, tag := range sd.Tags.List() {
+			tags[tag] = append(tags[tag], sd.UUID)
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	var storages *[]Storage
+	if storages, err = u.GetStorages(Private); err != nil {
+		return
+	}
+
+	state.Storages = append(state.Storages, *storages...)
+
+	var networks *[]Network
+	if networks, err = u.GetNetworks(); err != nil {
+		return
+	}
+
+	state.Networks = append(state.Networks, *networks...)
+
+	for name, uuids := range tags {
+		sort.Strings(uuids)
+		state.Tags = append(state.Tags, TagExport{Name: name, Servers: uuids})
+	}
+
+	state.sort()
+	s = &state
+	return
+}
+
+// ParseAccountState will parse an account state exported by ExportAccountState
+func ParseAccountState(bs []byte) (s *AccountState, err error) {
+	var state AccountState
+	if err = json.Unmarshal(bs, &state); err != nil {
+		return
+	}
+
+	if state.Version < 1 || state.Version > AccountStateVersion {
+		err = fmt.Errorf("%w: %d", ErrUnsupportedVersion, state.Version)
+		return
+	}
+
+	s = &state
+	return
+}
+
+// DiffAccountStates will return the resources added, removed and changed from one state to the other
+// Note: Changes are sorted by resource type and ID
+func DiffAccountStates(from, to *AccountState) (changes []StateChange, err error) {
+	var diffs = []struct {
+		resource string
+		from, to []stateResource
+	}{
+		{resourceServer, from.serverResources(), to.serverResources()},
+		{resourceStorage, from.storageResources(), to.storageResources()},
+		{resourceNetwork, from.networkResources(), to.networkResources()},
+		{resourceIPAddress, from.ipAddressResources(), to.ipAddressResources()},
+		{resourceTag, from.tagResources(), to.tagResources()},
+	}
+
+	for _, d := range diffs {
+		var resourceChanges []StateChange
+		if resourceChanges, err = diffResources(d.resource, d.from, d.to); err != nil {
+			return
+		}
+
+		changes = append(changes, resourceChanges...)
+	}
+
+	return
+}
+
+func (s *AccountState) sort() {
+	sort.Slice(s.Servers, func(i, j int) bool { return s.Servers[i].UUID < s.Servers[j].UUID })
+	sort.Slice(s.Storages, func(i, j int) bool { return s.Storages[i].UUID < s.Storages[j].UUID })
+	sort.Slice(s.Networks, func(i, j int) bool { return s.Networks[i].UUID < s.Networks[j].UUID })
+	sort.Slice(s.IPAddresses, func(i, j int) bool { return s.IPAddresses[i].Address < s.IPAddresses[j].Address })
+	sort.Slice(s.Tags, func(i, j int) bool { return s.Tags[i].Name < s.Tags[j].Name })
+}
+
+// stateResource represents a resource of an account state, keyed by its ID
+type stateResource struct {
+	id    string
+	name  string
+	value interface{}
+}
+
+func (s *AccountState) serverResources() (out []stateResource) {
+	for i := range s.Servers {
+		out = append(out, stateResource{s.Servers[i].UUID, s.Servers[i].Hostname, &s.Servers[i]})
+	}
+
+	return
+}
+
+func (s *AccountState) storageResources() (out []stateResource) {
+	for i := range s.Storages {
+		out = append(out, stateResource{s.Storages[i].UUID, s.Storages[i].Title, &s.Storages[i]})
+	}
+
+	return
+}
+
+func (s *AccountState) networkResources() (out []stateResource) {
+	for i := range s.Networks {
+		out = append(out, stateResource{s.Networks[i].UUID, s.Networks[i].Name, &s.Networks[i]})
+	}
+
+	return
+}
+
+func (s *AccountState) ipAddressResources() (out []stateResource) {
+	for i := range s.IPAddresses {
+		out = append(out, stateResource{s.IPAddresses[i].Address, "", &s.IPAddresses[i]})
+	}
+
+	return
+}
+
+func (s *AccountState) tagResources() (out []stateResource) {
+	for i := range s.Tags {
+		out = append(out, stateResource{s.Tags[i].Name, "", &s.Tags[i]})
+	}
+
+	return
+}
+
+func diffResources(resource string, from, to []stateResource) (changes []StateChange, err error) {
+	var previous = make(map[string]stateResource, len(from))
+	for _, r := range from {
+		previous[r.id] = r
+	}
+
+	var current = make(map[string]bool, len(to))
+	for _, r := range to {
+		current[r.id] = true
+
+		p, ok := previous[r.id]
+		if !ok {
+			changes = append(changes, StateChange{Type: ChangeAdded, Resource: resource, ID: r.id, Name: r.name})
+			continue
+		}
+
+		var fields []string
+		if fields, err = changedFields(p.value, r.value); err != nil {
+			return
+		}
+
+		if len(fields) > 0 {
+			changes = append(changes, StateChange{Type: ChangeChanged, Resource: resource, ID: r.id, Name: r.name, Fields: fields})
+		}
+	}
+
+	for _, r := range from {
+		if !current[r.id] {
+			changes = append(changes, StateChange{Type: ChangeRemoved, Resource: resource, ID: r.id, Name: r.name})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return
+}
+
+// changedFields will return the top-level JSON fields which differ between the values, sorted by name
+func changedFields(from, to interface{}) (fields []string, err error) {
+	var a, b map[string]json.RawMessage
+	if a, err = jsonFields(from); err != nil {
+		return
+	}
+
+	if b, err = jsonFields(to); err != nil {
+		return
+	}
+
+	for key, value := range b {
+		if !bytes.Equal(a[key], value) {
+			fields = append(fields, key)
+		}
+	}
+
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+
+	sort.Strings(fields)
+	return
+}
+
+func jsonFields(value interface{}) (fields map[string]json.RawMessage, err error) {
+	var bs []byte
+	if bs, err = json.Marshal(value); err != nil {
+		return
+	}
+
+	err = json.Unmarshal(bs, &fields)
+	return
+}
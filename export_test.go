@@ -0,0 +1,96 @@
+package upcloud_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func TestUpCloud_ExportAccountState(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	s := createServer(t, u)
+	srv.AddNetwork(upcloud.Network{Name: "backend", Type: "private", Zone: "us-chi1"})
+	srv.SetFirewallRules(s.UUID, []upcloud.FirewallRule{{Action: "accept", Direction: "in", Family: upcloud.IPv4, Protocol: "tcp", DestinationPortStart: "22", DestinationPortEnd: "22"}})
+
+	ctx := context.Background()
+	var old *upcloud.AccountState
+	if old, err = u.ExportAccountState(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if old.Version != upcloud.AccountStateVersion || old.Username != upcloudtest.Username {
+		t.Fatalf("invalid account state, received version %d for \"%s\"", old.Version, old.Username)
+	}
+
+	if len(old.Servers) != 1 || len(old.Servers[0].FirewallRules) != 1 {
+		t.Fatalf("invalid servers, expected a single server with a firewall rule and received %+v", old.Servers)
+	}
+
+	if len(old.Storages) != 1 || len(old.Networks) != 1 || len(old.IPAddresses) != 1 {
+		t.Fatalf("invalid resources, received %d storages, %d networks and %d IP addresses", len(old.Storages), len(old.Networks), len(old.IPAddresses))
+	}
+
+	// Round trip the export, as diffs usually compare against a stored export
+	var bs []byte
+	if bs, err = json.Marshal(old); err != nil {
+		t.Fatal(err)
+	}
+
+	if old, err = upcloud.ParseAccountState(bs); err != nil {
+		t.Fatal(err)
+	}
+
+	var serverDetails upcloud.ServerDetails
+	serverDetails.Title = "renamed"
+	if _, err = u.ModifyServer(s.UUID, &serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.TagServer(s.UUID, "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.CreateStorage(upcloud.CreateStorage{Size: 10, Title: "data", Zone: "us-chi1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var current *upcloud.AccountState
+	if current, err = u.ExportAccountState(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []upcloud.StateChange
+	if changes, err = upcloud.DiffAccountStates(old, current); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = map[string]upcloud.ChangeType{"server": upcloud.ChangeChanged, "storage": upcloud.ChangeAdded, "tag": upcloud.ChangeAdded}
+	if len(changes) != len(expected) {
+		t.Fatalf("invalid number of changes, expected %d and received %d: %v", len(expected), len(changes), changes)
+	}
+
+	for _, c := range changes {
+		if expected[c.Resource] != c.Type {
+			t.Fatalf("invalid change, expected %s %s and received %v", c.Resource, expected[c.Resource], c)
+		}
+	}
+
+	if fields := changes[0].Fields; len(fields) != 2 || fields[0] != "tags" || fields[1] != "title" {
+		t.Fatalf("invalid changed fields, expected [tags title] and received %v", fields)
+	}
+}
+
+func TestParseAccountState(t *testing.T) {
+	var err error
+	if _, err = upcloud.ParseAccountState([]byte(`{"version": 99}`)); !errors.Is(err, upcloud.ErrUnsupportedVersion) {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrUnsupportedVersion, err)
+	}
+}
@@ -0,0 +1,59 @@
+package upcloud
+
+import (
+	"path"
+)
+
+// FirewallRule represents a firewall rule of an UpCloud server
+type FirewallRule struct {
+	// Action of matching packets: accept, reject or drop
+	Action  string `json:"action"`
+	Comment string `json:"comment,omitempty"`
+	// Direction of the traffic: in or out
+	Direction               string          `json:"direction"`
+	Family                  IPAddressFamily `json:"family,omitempty"`
+	ICMPType                string          `json:"icmp_type,omitempty"`
+	Position                StringInt       `json:"position,omitempty"`
+	Protocol                string          `json:"protocol,omitempty"`
+	DestinationAddressStart string          `json:"destination_address_start,omitempty"`
+	DestinationAddressEnd   string          `json:"destination_address_end,omitempty"`
+	DestinationPortStart    string          `json:"destination_port_start,omitempty"`
+	DestinationPortEnd      string          `json:"destination_port_end,omitempty"`
+	SourceAddressStart      string          `json:"source_address_start,omitempty"`
+	SourceAddressEnd        string          `json:"source_address_end,omitempty"`
+	SourcePortStart         string          `json:"source_port_start,omitempty"`
+	SourcePortEnd           string          `json:"source_port_end,omitempty"`
+}
+
+// FirewallRules represents a list of firewall rules
+type FirewallRules struct {
+	FirewallRule *[]FirewallRule `json:"firewall_rule"`
+}
+
+// List will return the firewall rules as a slice
+// Note: A nil slice is returned when the value or firewall rules are not set
+func (f *FirewallRules) List() []FirewallRule {
+	if f == nil || f.FirewallRule == nil {
+		return nil
+	}
+
+	return *f.FirewallRule
+}
+
+type getFirewallRulesResponse struct {
+	FirewallRules *FirewallRules `json:"firewall_rules"`
+}
+
+// GetFirewallRules gets the firewall rules of a server, in the order they are applied
+func (u *UpCloud) GetFirewallRules(uuid string) (p *[]FirewallRule, err error) {
+	var resp getFirewallRulesResponse
+	// Make request to "Get Firewall Rules" route
+	if err = u.request("GetFirewallRules", "GET", path.Join(RouteServer, uuid, "firewall_rule"), nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	rules := resp.FirewallRules.List()
+	p = &rules
+	return
+}
@@ -1,3 +1,5 @@
 module github.com/hatchify/upcloud-sdk
 
 go 1.14
+
+require gopkg.in/yaml.v3 v3.0.1
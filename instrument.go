@@ -0,0 +1,148 @@
+package upcloud
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// SpanPrefix is the prefix of every span name (e.g. "upcloud.CreateServer")
+const SpanPrefix = "upcloud."
+
+const (
+	// AttributeZone is the span attribute key of the zone of the server
+	AttributeZone = "upcloud.zone"
+	// AttributeServerUUID is the span attribute key of the UUID of the server
+	AttributeServerUUID = "upcloud.server.uuid"
+	// AttributeErrorCode is the span attribute key of the UpCloud error code
+	AttributeErrorCode = "upcloud.error.code"
+	// AttributeMethod is the span attribute key of the HTTP method
+	AttributeMethod = "http.request.method"
+	// AttributeStatusCode is the span attribute key of the HTTP status code
+	AttributeStatusCode = "http.response.status_code"
+)
+
+// Tracer is the interface used for tracing requests
+// Note: See the otel package for an OpenTelemetry implementation
+type Tracer interface {
+	// StartSpan is called before a request is sent
+	StartSpan(name string) Span
+}
+
+// Span represents a single traced request
+type Span interface {
+	// SetAttribute is called once the request has completed, attributes which are unknown are not set
+	SetAttribute(key string, value interface{})
+	// End is called last with the error of the request, if any
+	End(err error)
+}
+
+// Metrics is the interface used for recording request metrics
+// Note: See the otel package for an OpenTelemetry implementation
+type Metrics interface {
+	RecordRequest(metric RequestMetric)
+}
+
+// RequestMetric represents the outcome of a single request
+type RequestMetric struct {
+	// Operation is the name of the SDK method (e.g. "CreateServer")
+	Operation string
+	Method    string
+	// StatusCode is zero when no response was received
+	StatusCode int
+	// ErrorCode is the UpCloud error code, if any (e.g. "SERVER_NOT_FOUND")
+	ErrorCode string
+	Latency   time.Duration
+	Err       error
+}
+
+// SetTracer will set the tracer used for requests
+func (u *UpCloud) SetTracer(tracer Tracer) {
+	u.tracer = tracer
+}
+
+// SetMetrics will set the metrics recorder used for requests
+func (u *UpCloud) SetMetrics(metrics Metrics) {
+	u.metrics = metrics
+}
+
+// startSpan will start the span of a request, nil is returned when tracing is disabled
+func (u *UpCloud) startSpan(operation string) Span {
+	if u.tracer == nil {
+		return nil
+	}
+
+	return u.tracer.StartSpan(SpanPrefix + operation)
+}
+
+// instrumentRequest will end the span and record the metrics of a completed request
+func (u *UpCloud) instrumentRequest(span Span, r *requestInfo, resp interface{}) {
+	if span == nil && u.metrics == nil {
+		return
+	}
+
+	var errorCode = getErrorCode(r.err)
+	if span != nil {
+		span.SetAttribute(AttributeMethod, r.method)
+		if r.status != 0 {
+			span.SetAttribute(AttributeStatusCode, r.status)
+		}
+
+		zone, uuid := getServerAttributes(r.endpoint, resp)
+		if zone != "" {
+			span.SetAttribute(AttributeZone, zone)
+		}
+
+		if uuid != "" {
+			span.SetAttribute(AttributeServerUUID, uuid)
+		}
+
+		if errorCode != "" {
+			span.SetAttribute(AttributeErrorCode, errorCode)
+		}
+
+		span.End(r.err)
+	}
+
+	if u.metrics != nil {
+		u.metrics.RecordRequest(RequestMetric{
+			Operation:  r.operation,
+			Method:     r.method,
+			StatusCode: r.status,
+			ErrorCode:  errorCode,
+			Latency:    r.latency,
+			Err:        r.err,
+		})
+	}
+}
+
+// getErrorCode will return the UpCloud error code of the error, if any
+func getErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return ""
+}
+
+// getServerAttributes will return the zone and server UUID of a request
+// Note: The UUID is taken from the endpoint (e.g. "server/<uuid>/stop") and falls back to the response
+func getServerAttributes(endpoint string, resp interface{}) (zone, uuid string) {
+	var parts = strings.Split(endpoint, "/")
+	if len(parts) > 1 && parts[0] == RouteServer {
+		uuid = parts[1]
+	}
+
+	wrapper, ok := resp.(*serverDetailsWrapper)
+	if !ok || wrapper.ServerDetails == nil {
+		return
+	}
+
+	zone = wrapper.ServerDetails.Zone
+	if uuid == "" {
+		uuid = wrapper.ServerDetails.UUID
+	}
+
+	return
+}
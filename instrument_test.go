@@ -0,0 +1,89 @@
+package upcloud
+
+import (
+	"errors"
+	"testing"
+)
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) StartSpan(name string) Span {
+	var s = testSpan{name: name, attributes: make(map[string]interface{})}
+	t.spans = append(t.spans, &s)
+	return &s
+}
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type testMetrics struct {
+	metrics []RequestMetric
+}
+
+func (m *testMetrics) RecordRequest(metric RequestMetric) {
+	m.metrics = append(m.metrics, metric)
+}
+
+func TestUpCloud_SetTracer(t *testing.T) {
+	var err error
+	u := setup(t)
+
+	var (
+		tracer  testTracer
+		metrics testMetrics
+	)
+
+	u.SetTracer(&tracer)
+	u.SetMetrics(&metrics)
+
+	if _, err = u.GetServerDetails("00334194-a6af-4fac-8eae-e098184c5e55"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("invalid number of spans, expected %d and received %d", 1, len(tracer.spans))
+	}
+
+	var span = tracer.spans[0]
+	if span.name != "upcloud.GetServerDetails" || !span.ended || span.err != nil {
+		t.Fatalf("invalid span, received %+v", span)
+	}
+
+	if span.attributes[AttributeServerUUID] != "00334194-a6af-4fac-8eae-e098184c5e55" {
+		t.Fatalf("invalid server UUID attribute, received %v", span.attributes[AttributeServerUUID])
+	}
+
+	if span.attributes[AttributeZone] != "us-chi1" {
+		t.Fatalf("invalid zone attribute, received %v", span.attributes[AttributeZone])
+	}
+
+	if len(metrics.metrics) != 1 || metrics.metrics[0].Operation != "GetServerDetails" || metrics.metrics[0].Method != "GET" {
+		t.Fatalf("invalid metrics, received %+v", metrics.metrics)
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	var err error = &Error{Code: "SERVER_NOT_FOUND", Message: "The server does not exist."}
+	if code := getErrorCode(err); code != "SERVER_NOT_FOUND" {
+		t.Fatalf("invalid error code, expected \"%s\" and received \"%s\"", "SERVER_NOT_FOUND", code)
+	}
+
+	if code := getErrorCode(errors.New("connection refused")); code != "" {
+		t.Fatalf("invalid error code, expected none and received \"%s\"", code)
+	}
+}
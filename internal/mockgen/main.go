@@ -0,0 +1,271 @@
+// Command mockgen generates upcloudtest.MockClient from the Client interface
+//
+// Usage (see upcloudtest/mock.go):
+//
+//	go run ../internal/mockgen -source ../client.go -out mock_client.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	interfaceName = "Client"
+	mockName      = "MockClient"
+	sdkPackage    = "upcloud"
+	sdkImport     = "github.com/hatchify/upcloud-sdk"
+)
+
+func main() {
+	var (
+		source = flag.String("source", "client.go", "file declaring the Client interface")
+		out    = flag.String("out", "mock_client.go", "file to write the mock to")
+	)
+
+	flag.Parse()
+
+	var err error
+	var bs []byte
+	if bs, err = generate(*source); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(*out, bs, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate will return the formatted source of the mock of the interface declared within the source file
+func generate(source string) (bs []byte, err error) {
+	var fset = token.NewFileSet()
+	var file *ast.File
+	if file, err = parser.ParseFile(fset, source, nil, 0); err != nil {
+		return
+	}
+
+	var iface *ast.InterfaceType
+	if iface = findInterface(file); iface == nil {
+		err = fmt.Errorf("interface %s not found within %s", interfaceName, source)
+		return
+	}
+
+	var imports = map[string]string{sdkPackage: sdkImport, "sync": "sync"}
+	for _, spec := range file.Imports {
+		var path = strings.Trim(spec.Path.Value, `"`)
+		imports[path[strings.LastIndex(path, "/")+1:]] = path
+	}
+
+	var g generator
+	g.fset = fset
+	g.used = map[string]bool{sdkPackage: true, "sync": true}
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			err = fmt.Errorf("embedded interfaces are not supported")
+			return
+		}
+
+		for _, name := range field.Names {
+			g.method(name.Name, fn)
+		}
+	}
+
+	var paths []string
+	for name := range g.used {
+		paths = append(paths, imports[name])
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		if isStandard(paths[i]) != isStandard(paths[j]) {
+			return isStandard(paths[i])
+		}
+
+		return paths[i] < paths[j]
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\npackage upcloudtest\n\nimport (\n", source)
+	for i, path := range paths {
+		// Standard library imports are grouped first
+		if i > 0 && isStandard(paths[i-1]) && !isStandard(path) {
+			buf.WriteString("\n")
+		}
+
+		if path == sdkImport {
+			fmt.Fprintf(&buf, "\t%s %q\n", sdkPackage, path)
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+
+	fmt.Fprintf(&buf, ")\n\nvar _ %s.%s = &%s{}\n\n", sdkPackage, interfaceName, mockName)
+	fmt.Fprintf(&buf, "// %s is a mock implementation of %s.%s which records every call\n", mockName, sdkPackage, interfaceName)
+	fmt.Fprintf(&buf, "// Note: Methods return zero values unless their func is set\n")
+	fmt.Fprintf(&buf, "type %s struct {\n\tmux   sync.Mutex\n\tcalls []Call\n\n%s}\n%s", mockName, g.fields.String(), g.methods.String())
+	return format.Source(buf.Bytes())
+}
+
+// isStandard will return whether or not the import path belongs to the standard library
+func isStandard(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
+
+func findInterface(file *ast.File) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			var ts = spec.(*ast.TypeSpec)
+			if iface, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == interfaceName {
+				return iface
+			}
+		}
+	}
+
+	return nil
+}
+
+type generator struct {
+	fset *token.FileSet
+	// Package names referenced by the generated code
+	used map[string]bool
+
+	fields  bytes.Buffer
+	methods bytes.Buffer
+}
+
+func (g *generator) method(name string, fn *ast.FuncType) {
+	var (
+		params   []string
+		args     []string
+		recorded []string
+		results  []string
+	)
+
+	for _, param := range fieldNames(fn.Params) {
+		var arg = param.name
+		var typ = g.expr(param.typ)
+		if ellipsis, ok := param.typ.(*ast.Ellipsis); ok {
+			arg += "..."
+			typ = "..." + g.expr(ellipsis.Elt)
+		}
+
+		params = append(params, param.name+" "+typ)
+		args = append(args, arg)
+		recorded = append(recorded, param.name)
+	}
+
+	// Results are renamed so they cannot collide with parameters or the receiver
+	for i, result := range fieldNames(fn.Results) {
+		results = append(results, fmt.Sprintf("r%d %s", i, g.expr(result.typ)))
+	}
+
+	var signature = fmt.Sprintf("(%s)", strings.Join(params, ", "))
+	var funcType = fmt.Sprintf("func%s", signature)
+	if len(results) > 0 {
+		signature += fmt.Sprintf(" (%s)", strings.Join(results, ", "))
+		funcType += fmt.Sprintf(" (%s)", strings.Join(results, ", "))
+	}
+
+	fmt.Fprintf(&g.fields, "\t%sFunc %s\n", name, funcType)
+	if len(results) > 0 {
+		fmt.Fprintf(&g.methods, "\n// %s will record the call and return the result of %sFunc\n", name, name)
+	} else {
+		fmt.Fprintf(&g.methods, "\n// %s will record the call and call %sFunc\n", name, name)
+	}
+
+	fmt.Fprintf(&g.methods, "func (m *%s) %s%s {\n", mockName, name, signature)
+	fmt.Fprintf(&g.methods, "\tm.record(%q", name)
+	for _, arg := range recorded {
+		fmt.Fprintf(&g.methods, ", %s", arg)
+	}
+
+	fmt.Fprintf(&g.methods, ")\n\tif m.%sFunc == nil {\n\t\treturn\n\t}\n\n", name)
+	if len(results) > 0 {
+		fmt.Fprintf(&g.methods, "\treturn m.%sFunc(%s)\n}\n", name, strings.Join(args, ", "))
+		return
+	}
+
+	fmt.Fprintf(&g.methods, "\tm.%sFunc(%s)\n}\n", name, strings.Join(args, ", "))
+}
+
+// expr will return the source of the type expression with types of the SDK package qualified
+func (g *generator) expr(expr ast.Expr) string {
+	expr = qualify(expr)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				g.used[pkg.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, expr)
+	return buf.String()
+}
+
+// qualify will return a copy of the type expression with exported identifiers prefixed by the SDK package
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent(sdkPackage), Sel: ast.NewIdent(e.Name)}
+		}
+
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt)}
+	default:
+		// Selector expressions are already qualified
+		return e
+	}
+}
+
+type namedField struct {
+	name string
+	typ  ast.Expr
+}
+
+// fieldNames will return the fields of the list, unnamed fields are named by their index (e.g. "a0")
+func fieldNames(list *ast.FieldList) (fields []namedField) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		if len(field.Names) == 0 {
+			fields = append(fields, namedField{name: fmt.Sprintf("a%d", len(fields)), typ: field.Type})
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields = append(fields, namedField{name: name.Name, typ: field.Type})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Ansible represents an Ansible dynamic inventory, as returned by inventory scripts for --list
+// Note: Hosts are grouped by zone, plan and tag (e.g. "zone_fi_hel1", "plan_1xCPU_1GB" and "tag_web")
+type Ansible struct {
+	Groups   map[string][]string
+	HostVars map[string]map[string]interface{}
+}
+
+// NewAnsible will return the Ansible inventory of the provided hosts
+// Note: The ansible_host of each host is its public address, or its private address when private is set.
+// Hosts are named by hostname, hostnames shared by several servers are suffixed by zone (e.g. "web-fi-hel1"),
+// or by UUID when shared within the same zone
+func NewAnsible(hosts []Host, private bool) (a *Ansible) {
+	a = &Ansible{
+		Groups:   make(map[string][]string),
+		HostVars: make(map[string]map[string]interface{}, len(hosts)),
+	}
+
+	var names = hostNames(hosts)
+	for i := range hosts {
+		var h, name = &hosts[i], names[i]
+		a.HostVars[name] = hostVars(h, private)
+		a.add(groupName("zone", h.Zone), name)
+		a.add(groupName("plan", h.Plan), name)
+		for _, tag := range h.Tags {
+			a.add(groupName("tag", tag), name)
+		}
+	}
+
+	for _, members := range a.Groups {
+		sort.Strings(members)
+	}
+
+	return
+}
+
+// MarshalJSON will encode the inventory in the format expected by Ansible
+func (a *Ansible) MarshalJSON() (bs []byte, err error) {
+	var groups = make([]string, 0, len(a.Groups))
+	var out = make(map[string]interface{}, len(a.Groups)+2)
+	for name, members := range a.Groups {
+		groups = append(groups, name)
+		out[name] = map[string]interface{}{"hosts": members}
+	}
+
+	sort.Strings(groups)
+	out["all"] = map[string]interface{}{"children": groups}
+	out["_meta"] = map[string]interface{}{"hostvars": a.HostVars}
+	return json.Marshal(out)
+}
+
+func (a *Ansible) add(group, hostname string) {
+	if group == "" {
+		return
+	}
+
+	a.Groups[group] = append(a.Groups[group], hostname)
+}
+
+func hostVars(h *Host, private bool) map[string]interface{} {
+	var vars = map[string]interface{}{
+		"upcloud_uuid":     h.UUID,
+		"upcloud_hostname": h.Hostname,
+		"upcloud_title":    h.Title,
+		"upcloud_zone":     h.Zone,
+		"upcloud_plan":     h.Plan,
+		"upcloud_state":    h.State,
+		"upcloud_tags":     nonNil(h.Tags),
+
+		"upcloud_public_ipv4":  nonNil(h.PublicIPv4),
+		"upcloud_public_ipv6":  nonNil(h.PublicIPv6),
+		"upcloud_private_ipv4": nonNil(h.PrivateIPv4),
+		"upcloud_private_ipv6": nonNil(h.PrivateIPv6),
+	}
+
+	if address := h.Address(private); address != "" {
+		vars["ansible_host"] = address
+	}
+
+	return vars
+}
+
+// hostNames will return the inventory name of each host, keeping names unique when hostnames are shared
+func hostNames(hosts []Host) (names []string) {
+	var hostnames = make(map[string]int, len(hosts))
+	var zones = make(map[string]int, len(hosts))
+	for _, h := range hosts {
+		hostnames[h.Hostname]++
+		zones[h.Hostname+"-"+h.Zone]++
+	}
+
+	names = make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		switch {
+		case hostnames[h.Hostname] == 1:
+			names = append(names, h.Hostname)
+		case zones[h.Hostname+"-"+h.Zone] == 1:
+			names = append(names, h.Hostname+"-"+h.Zone)
+		default:
+			names = append(names, h.Hostname+"-"+h.UUID)
+		}
+	}
+
+	return
+}
+
+// groupName will return a valid Ansible group name, replacing invalid characters with underscores
+func groupName(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return prefix + "_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
+
+// nonNil will return an empty slice in place of nil, so lists are encoded as [] rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
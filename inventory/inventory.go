@@ -0,0 +1,110 @@
+// Package inventory exports UpCloud servers for configuration management and service discovery
+package inventory
+
+import (
+	"context"
+	"sort"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+const (
+	accessPublic  = "public"
+	accessUtility = "utility"
+	accessPrivate = "private"
+)
+
+// Host represents a server along with its addresses
+type Host struct {
+	UUID     string              `json:"uuid"`
+	Hostname string              `json:"hostname"`
+	Title    string              `json:"title"`
+	Zone     string              `json:"zone"`
+	Plan     string              `json:"plan"`
+	State    upcloud.ServerState `json:"state"`
+	Tags     []string            `json:"tags,omitempty"`
+
+	PublicIPv4 []string `json:"public_ipv4,omitempty"`
+	PublicIPv6 []string `json:"public_ipv6,omitempty"`
+	// Addresses of utility and private networks
+	PrivateIPv4 []string `json:"private_ipv4,omitempty"`
+	PrivateIPv6 []string `json:"private_ipv6,omitempty"`
+}
+
+// Address will return the address to reach the host by
+// Note: IPv4 addresses are preferred, an empty string is returned when the host has no matching addresses
+func (h *Host) Address(private bool) string {
+	var candidates = [][]string{h.PublicIPv4, h.PublicIPv6}
+	if private {
+		candidates = [][]string{h.PrivateIPv4, h.PrivateIPv6}
+	}
+
+	for _, addresses := range candidates {
+		if len(addresses) > 0 {
+			return addresses[0]
+		}
+	}
+
+	return ""
+}
+
+// Collect will return the hosts of every server, sorted by hostname
+func Collect(ctx context.Context, u upcloud.Client) (hosts []Host, err error) {
+	var servers *[]upcloud.Server
+	if servers, err = u.GetServers(); err != nil {
+		return
+	}
+
+	hosts = make([]Host, 0, len(*servers))
+	for _, s := range *servers {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var sd *upcloud.ServerDetails
+		if sd, err = u.GetServerDetails(s.UUID); err != nil {
+			return
+		}
+
+		hosts = append(hosts, newHost(sd))
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Hostname != hosts[j].Hostname {
+			return hosts[i].Hostname < hosts[j].Hostname
+		}
+
+		return hosts[i].UUID < hosts[j].UUID
+	})
+
+	return
+}
+
+func newHost(sd *upcloud.ServerDetails) (h Host) {
+	h.UUID = sd.UUID
+	h.Hostname = sd.Hostname
+	h.Title = sd.Title
+	h.Zone = sd.Zone
+	h.Plan = sd.Plan
+	h.State = sd.State
+	h.Tags = append(h.Tags, sd.Tags.List()...)
+
+	for _, ip := range sd.IPAddresses.List() {
+		if ip.Address == "" {
+			continue
+		}
+
+		switch {
+		case ip.Access == accessPublic && ip.Family == upcloud.IPv6:
+			h.PublicIPv6 = append(h.PublicIPv6, ip.Address)
+		case ip.Access == accessPublic:
+			h.PublicIPv4 = append(h.PublicIPv4, ip.Address)
+		case (ip.Access == accessUtility || ip.Access == accessPrivate) && ip.Family == upcloud.IPv6:
+			h.PrivateIPv6 = append(h.PrivateIPv6, ip.Address)
+		case ip.Access == accessUtility || ip.Access == accessPrivate:
+			h.PrivateIPv4 = append(h.PrivateIPv4, ip.Address)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func testHosts(t *testing.T) (hosts []Host) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	for _, hostname := range []string{"web-2", "web-1"} {
+		var serverDetails *upcloud.ServerDetails
+		if serverDetails, err = upcloud.NewServerBuilder("fi-hel1", hostname).
+			Plan("1xCPU-1GB").
+			CloneTemplate(upcloudtest.TemplateUbuntu, hostname+"-disk", 25).
+			Tags("web", "prod-eu").
+			Build(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = u.CreateServer(serverDetails); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hosts, err = Collect(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+func TestCollect(t *testing.T) {
+	hosts := testHosts(t)
+	if len(hosts) != 2 || hosts[0].Hostname != "web-1" {
+		t.Fatalf("invalid hosts, expected web-1 and web-2 and received %+v", hosts)
+	}
+
+	var h = hosts[0]
+	if len(h.PublicIPv4) != 1 || len(h.PublicIPv6) != 1 || len(h.PrivateIPv4) != 1 {
+		t.Fatalf("invalid addresses, expected a public IPv4, a public IPv6 and a private IPv4 and received %+v", h)
+	}
+
+	if address := h.Address(true); address != h.PrivateIPv4[0] {
+		t.Fatalf("invalid address, expected \"%s\" and received \"%s\"", h.PrivateIPv4[0], address)
+	}
+}
+
+func TestNewAnsible(t *testing.T) {
+	var err error
+	hosts := testHosts(t)
+
+	var bs []byte
+	if bs, err = json.Marshal(NewAnsible(hosts, false)); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Meta struct {
+			HostVars map[string]map[string]interface{} `json:"hostvars"`
+		} `json:"_meta"`
+		Zone struct {
+			Hosts []string `json:"hosts"`
+		} `json:"zone_fi_hel1"`
+		Tag struct {
+			Hosts []string `json:"hosts"`
+		} `json:"tag_prod_eu"`
+		Plan struct {
+			Hosts []string `json:"hosts"`
+		} `json:"plan_1xCPU_1GB"`
+	}
+
+	if err = json.Unmarshal(bs, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.Zone.Hosts) != 2 || len(out.Tag.Hosts) != 2 || len(out.Plan.Hosts) != 2 {
+		t.Fatalf("invalid groups, received %s", bs)
+	}
+
+	if host := out.Meta.HostVars["web-1"]["ansible_host"]; host != hosts[0].PublicIPv4[0] {
+		t.Fatalf("invalid ansible_host, expected \"%s\" and received \"%v\"", hosts[0].PublicIPv4[0], host)
+	}
+}
+
+func TestNewAnsible_SharedHostnames(t *testing.T) {
+	var hosts = []Host{
+		{UUID: "00000000-0000-4000-8000-000000000001", Hostname: "web", Zone: "fi-hel1"},
+		{UUID: "00000000-0000-4000-8000-000000000002", Hostname: "web", Zone: "de-fra1"},
+		{UUID: "00000000-0000-4000-8000-000000000003", Hostname: "web", Zone: "de-fra1"},
+		{UUID: "00000000-0000-4000-8000-000000000004", Hostname: "db", Zone: "de-fra1"},
+	}
+
+	a := NewAnsible(hosts, false)
+	if len(a.HostVars) != len(hosts) {
+		t.Fatalf("invalid number of hosts, expected %d and received %d", len(hosts), len(a.HostVars))
+	}
+
+	for _, name := range []string{"web-fi-hel1", "web-" + hosts[1].UUID, "web-" + hosts[2].UUID, "db"} {
+		if _, ok := a.HostVars[name]; !ok {
+			t.Fatalf("invalid hosts, expected \"%s\" and received %v", name, a.HostVars)
+		}
+	}
+
+	if members := a.Groups["zone_de_fra1"]; len(members) != 3 {
+		t.Fatalf("invalid number of group members, expected %d and received %d", 3, len(members))
+	}
+}
+
+func TestNewPrometheus(t *testing.T) {
+	hosts := testHosts(t)
+	groups := NewPrometheus(hosts, 9100, true)
+	if len(groups) != 2 {
+		t.Fatalf("invalid number of target groups, expected %d and received %d", 2, len(groups))
+	}
+
+	if target := groups[0].Targets[0]; target != hosts[0].PrivateIPv4[0]+":9100" {
+		t.Fatalf("invalid target, expected \"%s\" and received \"%s\"", hosts[0].PrivateIPv4[0]+":9100", target)
+	}
+
+	if tags := groups[0].Labels["upcloud_tags"]; tags != ",web,prod-eu," {
+		t.Fatalf("invalid tags label, expected \"%s\" and received \"%s\"", ",web,prod-eu,", tags)
+	}
+}
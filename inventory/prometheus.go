@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// TargetGroup represents a Prometheus file_sd target group
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// NewPrometheus will return the Prometheus file_sd target groups of the provided hosts, one group per host
+// Note: Targets are the public address of each host and the port, or the private address when private is set.
+// Hosts without a matching address are skipped
+func NewPrometheus(hosts []Host, port int, private bool) (groups []TargetGroup) {
+	groups = []TargetGroup{}
+	for i := range hosts {
+		var h = &hosts[i]
+		var address = h.Address(private)
+		if address == "" {
+			continue
+		}
+
+		var labels = map[string]string{
+			"upcloud_uuid":     h.UUID,
+			"upcloud_hostname": h.Hostname,
+			"upcloud_zone":     h.Zone,
+			"upcloud_plan":     h.Plan,
+			"upcloud_state":    h.State.String(),
+		}
+
+		if len(h.Tags) > 0 {
+			// Surrounding commas allow matching single tags with regular expressions, e.g. ".*,web,.*"
+			labels["upcloud_tags"] = "," + strings.Join(h.Tags, ",") + ","
+		}
+
+		if len(h.PublicIPv4) > 0 {
+			labels["upcloud_public_ipv4"] = h.PublicIPv4[0]
+		}
+
+		if len(h.PrivateIPv4) > 0 {
+			labels["upcloud_private_ipv4"] = h.PrivateIPv4[0]
+		}
+
+		var target = net.JoinHostPort(address, strconv.Itoa(port))
+		groups = append(groups, TargetGroup{Targets: []string{target}, Labels: labels})
+	}
+
+	return
+}
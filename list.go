@@ -0,0 +1,264 @@
+package upcloud
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hatchify/requester"
+)
+
+// DefaultPageSize is the page size used by iterators when no limit is set
+const DefaultPageSize = 100
+
+// ListOptions represents the optional parameters of list requests
+type ListOptions struct {
+	// Maximum number of entries to return, 0 returns every entry
+	Limit int
+	// Number of entries to skip
+	Offset int
+	// Field to sort by, prefixed with "-" for descending order (e.g. "-created")
+	Sort string
+	// Labels to filter by, only supported for servers
+	Labels map[string]string
+}
+
+// queryOpts will return the request options for the list options
+func (l *ListOptions) queryOpts() (opts requester.Opts) {
+	var params []requester.QueryParam
+	if l.Limit > 0 {
+		params = append(params, requester.QueryParam{Key: "limit", Val: strconv.Itoa(l.Limit)})
+	}
+
+	if l.Offset > 0 {
+		params = append(params, requester.QueryParam{Key: "offset", Val: strconv.Itoa(l.Offset)})
+	}
+
+	if l.Sort != "" {
+		params = append(params, requester.QueryParam{Key: "sort", Val: l.Sort})
+	}
+
+	// Labels are sorted to keep requests deterministic
+	var keys = make([]string, 0, len(l.Labels))
+	for key := range l.Labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		params = append(params, requester.QueryParam{Key: "label", Val: key + "=" + l.Labels[key]})
+	}
+
+	if len(params) == 0 {
+		return
+	}
+
+	opts = requester.Opts{requester.NewQuery(params...)}
+	return
+}
+
+// withContext will return a modifier which binds the request to the provided context
+func withContext(ctx context.Context) requester.Modifier {
+	return func(request *http.Request, client *http.Client) (err error) {
+		*request = *request.WithContext(ctx)
+		return
+	}
+}
+
+// GetServersWithOptions gets the servers matching the provided list options
+func (u *UpCloud) GetServersWithOptions(options ListOptions) (p *[]Server, err error) {
+	return u.getServers(options.queryOpts())
+}
+
+// GetStoragesWithOptions gets the storages matching the provided filter and list options
+func (u *UpCloud) GetStoragesWithOptions(filter RouteGetStorageFilter, options ListOptions) (p *[]Storage, err error) {
+	return u.getStorages(filter, options.queryOpts())
+}
+
+func (u *UpCloud) getServers(opts requester.Opts) (p *[]Server, err error) {
+	var resp getServersResponse
+	// Make request to "Get Servers" route
+	if err = u.request("GetServers", "GET", RouteServer, opts, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	servers := resp.Servers.List()
+	p = &servers
+	return
+}
+
+func (u *UpCloud) getStorages(filter RouteGetStorageFilter, opts requester.Opts) (p *[]Storage, err error) {
+	var resp getStoragesResponse
+	// Make request to "Get Storages" route
+	if err = u.request("GetStorages", "GET", string(filter), opts, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	storages := resp.Storages.List()
+	p = &storages
+	return
+}
+
+// NewServerIterator will return an iterator which fetches servers lazily, one page at a time
+// Note: The limit of the options is used as the page size
+func (u *UpCloud) NewServerIterator(options ListOptions) *ServerIterator {
+	var it ServerIterator
+	it.u = u
+	it.p.init(options)
+	return &it
+}
+
+// ServerIterator iterates over servers one page at a time
+type ServerIterator struct {
+	u *UpCloud
+	p pager
+
+	page    []Server
+	current Server
+}
+
+// Next will advance the iterator, fetching the next page when needed
+// Note: False is returned when iteration is complete or an error was encountered (see Err)
+func (s *ServerIterator) Next(ctx context.Context) bool {
+	if len(s.page) == 0 && s.p.more(ctx) {
+		var servers *[]Server
+		servers, s.p.err = s.u.getServers(s.p.opts(ctx))
+		if s.p.err == nil && !s.p.advance(len(*servers), firstServerUUID(*servers)) {
+			s.page = *servers
+		}
+	}
+
+	if len(s.page) == 0 {
+		return false
+	}
+
+	s.current, s.page = s.page[0], s.page[1:]
+	return true
+}
+
+// Server will return the current server
+func (s *ServerIterator) Server() Server {
+	return s.current
+}
+
+// Err will return the error encountered during iteration, if any
+func (s *ServerIterator) Err() error {
+	return s.p.err
+}
+
+// NewStorageIterator will return an iterator which fetches storages lazily, one page at a time
+// Note: The limit of the options is used as the page size
+func (u *UpCloud) NewStorageIterator(filter RouteGetStorageFilter, options ListOptions) *StorageIterator {
+	var it StorageIterator
+	it.u = u
+	it.filter = filter
+	it.p.init(options)
+	return &it
+}
+
+// StorageIterator iterates over storages one page at a time
+type StorageIterator struct {
+	u      *UpCloud
+	p      pager
+	filter RouteGetStorageFilter
+
+	page    []Storage
+	current Storage
+}
+
+// Next will advance the iterator, fetching the next page when needed
+// Note: False is returned when iteration is complete or an error was encountered (see Err)
+func (s *StorageIterator) Next(ctx context.Context) bool {
+	if len(s.page) == 0 && s.p.more(ctx) {
+		var storages *[]Storage
+		storages, s.p.err = s.u.getStorages(s.filter, s.p.opts(ctx))
+		if s.p.err == nil && !s.p.advance(len(*storages), firstStorageUUID(*storages)) {
+			s.page = *storages
+		}
+	}
+
+	if len(s.page) == 0 {
+		return false
+	}
+
+	s.current, s.page = s.page[0], s.page[1:]
+	return true
+}
+
+// Storage will return the current storage
+func (s *StorageIterator) Storage() Storage {
+	return s.current
+}
+
+// Err will return the error encountered during iteration, if any
+func (s *StorageIterator) Err() error {
+	return s.p.err
+}
+
+// pager tracks the position of an iterator
+type pager struct {
+	options ListOptions
+	done    bool
+	err     error
+	// UUID of the first item of the previous page
+	first string
+}
+
+func (p *pager) init(options ListOptions) {
+	if options.Limit <= 0 {
+		options.Limit = DefaultPageSize
+	}
+
+	p.options = options
+}
+
+// more will return whether or not another page should be fetched
+func (p *pager) more(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	if p.err = ctx.Err(); p.err != nil {
+		return false
+	}
+
+	return true
+}
+
+func (p *pager) opts(ctx context.Context) requester.Opts {
+	return append(p.options.queryOpts(), withContext(ctx))
+}
+
+// advance will move past a page of n items, returning whether or not the page repeats the previous page
+// Note: Endpoints ignoring paging return the same page for every offset, repeated pages are discarded
+func (p *pager) advance(n int, first string) (repeated bool) {
+	if repeated = first != "" && first == p.first; repeated {
+		p.done = true
+		return
+	}
+
+	p.first = first
+	p.options.Offset += n
+	// A partial page is the last page, while a larger page means the endpoint ignores paging
+	p.done = n != p.options.Limit
+	return
+}
+
+func firstServerUUID(servers []Server) string {
+	if len(servers) == 0 {
+		return ""
+	}
+
+	return servers[0].UUID
+}
+
+func firstStorageUUID(storages []Storage) string {
+	if len(storages) == 0 {
+		return ""
+	}
+
+	return storages[0].UUID
+}
@@ -0,0 +1,141 @@
+package upcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hatchify/requester"
+)
+
+func TestServerIterator(t *testing.T) {
+	var err error
+	var servers = []Server{{UUID: "1"}, {UUID: "2"}, {UUID: "3"}, {UUID: "4"}, {UUID: "5"}}
+
+	var requests int
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		var page = servers[offset:]
+		if limit < len(page) {
+			page = page[:limit]
+		}
+
+		json.NewEncoder(w).Encode(getServersResponse{Servers: &Servers{Server: &page}})
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	var uuids []string
+	var it = u.NewServerIterator(ListOptions{Limit: 2})
+	for it.Next(context.Background()) {
+		uuids = append(uuids, it.Server().UUID)
+	}
+
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(uuids) != len(servers) {
+		t.Fatalf("invalid number of servers, expected %d and received %d", len(servers), len(uuids))
+	}
+
+	if requests != 3 {
+		t.Fatalf("invalid number of requests, expected %d and received %d", 3, requests)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	it = u.NewServerIterator(ListOptions{Limit: 2})
+	if it.Next(ctx) || it.Err() != context.Canceled {
+		t.Fatalf("invalid error, expected %v and received %v", context.Canceled, it.Err())
+	}
+}
+
+func TestServerIterator_IgnoredPaging(t *testing.T) {
+	var err error
+	var servers = []Server{{UUID: "1"}, {UUID: "2"}, {UUID: "3"}}
+
+	// The endpoint ignores limit and offset, returning every server on each request
+	var requests int
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(getServersResponse{Servers: &Servers{Server: &servers}})
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	var n int
+	var it = u.NewServerIterator(ListOptions{Limit: 2})
+	for it.Next(context.Background()) {
+		n++
+	}
+
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(servers) {
+		t.Fatalf("invalid number of servers, expected %d and received %d", len(servers), n)
+	}
+
+	if requests != 1 {
+		t.Fatalf("invalid number of requests, expected %d and received %d", 1, requests)
+	}
+}
+
+func TestStorageIterator_IgnoredPagingFullPage(t *testing.T) {
+	var err error
+	var storages = []Storage{{UUID: "1"}, {UUID: "2"}}
+
+	// The endpoint ignores limit and offset, returning exactly a full page on each request
+	var requests int
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(getStoragesResponse{Storages: &Storages{Storage: &storages}})
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	var n int
+	var it = u.NewStorageIterator(Private, ListOptions{Limit: 2})
+	for it.Next(context.Background()) && n < 10 {
+		n++
+	}
+
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(storages) {
+		t.Fatalf("invalid number of storages, expected %d and received %d", len(storages), n)
+	}
+
+	if requests != 2 {
+		t.Fatalf("invalid number of requests, expected %d and received %d", 2, requests)
+	}
+}
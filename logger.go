@@ -0,0 +1,166 @@
+package upcloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hatchify/requester"
+)
+
+// Redacted is the value logged in place of sensitive values
+const Redacted = "[REDACTED]"
+
+// Logger is the interface used for logging requests
+// Note: The method set matches *slog.Logger, keysAndValues are alternating keys and values
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// redactedHeaders are the headers which are always masked
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// SetLogger will set the logger used for requests
+// Note: Failed requests are logged as errors, every request is logged when debug is enabled
+func (u *UpCloud) SetLogger(logger Logger) {
+	u.logger = logger
+}
+
+// SetDebug will enable or disable debug logging of every request along with redacted bodies
+func (u *UpCloud) SetDebug(debug bool) {
+	u.debug = debug
+}
+
+// requestInfo contains the details of a single request for logging and instrumentation
+type requestInfo struct {
+	operation string
+	method    string
+	endpoint  string
+	header    http.Header
+	body      []byte
+
+	status   int
+	response []byte
+	latency  time.Duration
+	err      error
+}
+
+// captureHeaders will return a modifier which copies the request headers for logging
+// Note: This modifier must be applied after every other modifier
+func (r *requestInfo) captureHeaders() requester.Modifier {
+	return func(request *http.Request, client *http.Client) (err error) {
+		r.header = request.Header.Clone()
+		return
+	}
+}
+
+// captureResponse will read the response body for logging and replace it with a readable copy
+func (r *requestInfo) captureResponse(res *http.Response) (err error) {
+	r.status = res.StatusCode
+	if r.response, err = ioutil.ReadAll(res.Body); err != nil {
+		return
+	}
+
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(r.response))
+	return
+}
+
+func (u *UpCloud) logRequest(r *requestInfo) {
+	if u.logger == nil {
+		return
+	}
+
+	var keysAndValues = []interface{}{
+		"operation", r.operation,
+		"method", r.method,
+		"path", u.getURL(r.endpoint),
+		"status", r.status,
+		"latency", r.latency,
+	}
+
+	if u.debug {
+		keysAndValues = append(keysAndValues,
+			"request_headers", redactHeaders(r.header),
+			"request_body", redactBody(r.body),
+			"response_body", redactBody(r.response),
+		)
+	}
+
+	switch {
+	case r.err != nil:
+		u.logger.Error("UpCloud request failed", append(keysAndValues, "error", r.err)...)
+	case u.debug:
+		u.logger.Debug("UpCloud request", keysAndValues...)
+	}
+}
+
+// redactHeaders will return the headers with credentials masked
+func redactHeaders(header http.Header) map[string]string {
+	var out = make(map[string]string, len(header))
+	for key := range header {
+		out[key] = header.Get(key)
+	}
+
+	for _, key := range redactedHeaders {
+		if _, ok := out[key]; ok {
+			out[key] = Redacted
+		}
+	}
+
+	return out
+}
+
+// redactBody will return the JSON body with passwords masked
+// Note: Bodies which are not valid JSON are omitted to avoid leaking anything sensitive
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[NON-JSON BODY OMITTED]"
+	}
+
+	bs, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[BODY OMITTED]"
+	}
+
+	return string(bs)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isSensitiveKey(key) {
+				v[key] = Redacted
+				continue
+			}
+
+			v[key] = redactValue(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child)
+		}
+	}
+
+	return value
+}
+
+// isSensitiveKey will return whether or not the key holds a credential (e.g. "password", "remote_access_password")
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret") || strings.Contains(key, "token")
+}
@@ -0,0 +1,190 @@
+// Package metadata reads instance metadata from within an UpCloud server
+// Note: The metadata service must be enabled for the server (see UpCloud.SetMetadata)
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultEndpoint is the link-local endpoint of the UpCloud metadata service
+	DefaultEndpoint = "http://169.254.169.254"
+	// DefaultTimeout is the request timeout used by clients
+	DefaultTimeout = 2 * time.Second
+)
+
+const (
+	// RouteMetadata gets all the metadata as JSON
+	RouteMetadata = "metadata/v1.json"
+	// RouteInstanceID gets the UUID of the server
+	RouteInstanceID = "metadata/v1/instance_id"
+	// RouteHostname gets the hostname of the server
+	RouteHostname = "metadata/v1/hostname"
+	// RouteUserData gets the user data of the server
+	RouteUserData = "metadata/v1/user_data"
+)
+
+// ErrNotFound is returned when the metadata service does not have the requested value
+var ErrNotFound = errors.New("metadata not found")
+
+// New will return a new metadata client for the provided endpoint
+// Note: An empty endpoint will use DefaultEndpoint
+func New(endpoint string) (c *Client, err error) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	var client Client
+	if client.endpoint, err = url.Parse(strings.TrimRight(endpoint, "/")); err != nil {
+		return
+	}
+
+	client.hc = &http.Client{Timeout: DefaultTimeout}
+	c = &client
+	return
+}
+
+// Client reads metadata from the UpCloud metadata service
+type Client struct {
+	endpoint *url.URL
+	hc       *http.Client
+}
+
+// SetHTTPClient will set the HTTP client used for requests
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.hc = hc
+}
+
+// Available will return whether or not the metadata service is reachable
+// Note: This can be used to detect if the code is running on an UpCloud server
+func (c *Client) Available(ctx context.Context) bool {
+	_, err := c.InstanceID(ctx)
+	return err == nil
+}
+
+// Get will get all the metadata of the server
+func (c *Client) Get(ctx context.Context) (m *Metadata, err error) {
+	var body io.ReadCloser
+	if body, err = c.request(ctx, RouteMetadata); err != nil {
+		return
+	}
+	defer body.Close()
+
+	var metadata Metadata
+	if err = json.NewDecoder(body).Decode(&metadata); err != nil {
+		return
+	}
+
+	m = &metadata
+	return
+}
+
+// InstanceID will get the UUID of the server
+func (c *Client) InstanceID(ctx context.Context) (uuid string, err error) {
+	return c.getString(ctx, RouteInstanceID)
+}
+
+// Hostname will get the hostname of the server
+func (c *Client) Hostname(ctx context.Context) (hostname string, err error) {
+	return c.getString(ctx, RouteHostname)
+}
+
+// UserData will get the user data of the server as is, whitespace is significant to scripts and YAML
+func (c *Client) UserData(ctx context.Context) (userData string, err error) {
+	var bs []byte
+	if bs, err = c.getBytes(ctx, RouteUserData); err != nil {
+		return
+	}
+
+	userData = string(bs)
+	return
+}
+
+// PublicKeys will get the SSH public keys of the server
+func (c *Client) PublicKeys(ctx context.Context) (keys []string, err error) {
+	var m *Metadata
+	if m, err = c.Get(ctx); err != nil {
+		return
+	}
+
+	keys = m.PublicKeys
+	return
+}
+
+// Tags will get the tags of the server
+func (c *Client) Tags(ctx context.Context) (tags []string, err error) {
+	var m *Metadata
+	if m, err = c.Get(ctx); err != nil {
+		return
+	}
+
+	tags = m.Tags
+	return
+}
+
+// Network will get the network configuration of the server
+func (c *Client) Network(ctx context.Context) (n *Network, err error) {
+	var m *Metadata
+	if m, err = c.Get(ctx); err != nil {
+		return
+	}
+
+	n = &m.Network
+	return
+}
+
+// getString will return the value of the route, without surrounding whitespace
+func (c *Client) getString(ctx context.Context, route string) (value string, err error) {
+	var bs []byte
+	if bs, err = c.getBytes(ctx, route); err != nil {
+		return
+	}
+
+	value = strings.TrimSpace(string(bs))
+	return
+}
+
+func (c *Client) getBytes(ctx context.Context, route string) (bs []byte, err error) {
+	var body io.ReadCloser
+	if body, err = c.request(ctx, route); err != nil {
+		return
+	}
+	defer body.Close()
+
+	return ioutil.ReadAll(body)
+}
+
+func (c *Client) request(ctx context.Context, route string) (body io.ReadCloser, err error) {
+	var req *http.Request
+	if req, err = http.NewRequest("GET", c.endpoint.String()+"/"+route, nil); err != nil {
+		return
+	}
+
+	var res *http.Response
+	if res, err = c.hc.Do(req.WithContext(ctx)); err != nil {
+		return
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		res.Body.Close()
+		err = ErrNotFound
+		return
+	case res.StatusCode >= 400:
+		res.Body.Close()
+		err = fmt.Errorf("metadata service returned status %d for %s", res.StatusCode, route)
+		return
+	}
+
+	body = res.Body
+	return
+}
@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testMetadata = `{
+	"cloud_name": "upcloud",
+	"instance_id": "00334194-a6af-4fac-8eae-e098184c5e55",
+	"hostname": "sdk-test-machine",
+	"platform": "servers",
+	"region": "us-chi1",
+	"public_keys": ["ssh-ed25519 AAAA sdk@test"],
+	"tags": ["web"],
+	"user_data": "#cloud-config\n",
+	"network": {
+		"interfaces": [{
+			"index": 1,
+			"ip_addresses": [{"address": "209.50.53.216", "dhcp": true, "family": "IPv4", "floating": false}],
+			"mac": "56:0b:73:d7:39:34",
+			"type": "public"
+		}],
+		"dns": ["94.237.127.9"]
+	}
+}`
+
+// testUserData is indented and ends with an empty line, which are significant to scripts and YAML
+const testUserData = "  #!/bin/sh\n  echo ok\n\n"
+
+func testServer(t *testing.T) (c *Client, cleanup func()) {
+	var err error
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/"+RouteMetadata, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+
+	mux.HandleFunc("/"+RouteInstanceID, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("00334194-a6af-4fac-8eae-e098184c5e55\n"))
+	})
+
+	mux.HandleFunc("/"+RouteUserData, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testUserData))
+	})
+
+	var srv = httptest.NewServer(mux)
+	if c, err = New(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	cleanup = srv.Close
+	return
+}
+
+func TestClient_Get(t *testing.T) {
+	var err error
+	c, cleanup := testServer(t)
+	defer cleanup()
+
+	var m *Metadata
+	if m, err = c.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Hostname != "sdk-test-machine" || m.Region != "us-chi1" {
+		t.Fatalf("invalid metadata, received %+v", m)
+	}
+
+	if len(m.Network.Interfaces) != 1 || m.Network.Interfaces[0].IPAddresses[0].Address != "209.50.53.216" {
+		t.Fatalf("invalid network, received %+v", m.Network)
+	}
+
+	var keys []string
+	if keys, err = c.PublicKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 {
+		t.Fatalf("invalid number of public keys, expected %d and received %d", 1, len(keys))
+	}
+}
+
+func TestClient_InstanceID(t *testing.T) {
+	var err error
+	c, cleanup := testServer(t)
+	defer cleanup()
+
+	var uuid string
+	if uuid, err = c.InstanceID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if uuid != "00334194-a6af-4fac-8eae-e098184c5e55" {
+		t.Fatalf("invalid instance ID, received \"%s\"", uuid)
+	}
+
+	if !c.Available(context.Background()) {
+		t.Fatal("expected metadata service to be available")
+	}
+
+	if _, err = c.Hostname(context.Background()); err != ErrNotFound {
+		t.Fatalf("invalid error, expected %v and received %v", ErrNotFound, err)
+	}
+}
+
+func TestClient_UserData(t *testing.T) {
+	var err error
+	c, cleanup := testServer(t)
+	defer cleanup()
+
+	var userData string
+	if userData, err = c.UserData(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if userData != testUserData {
+		t.Fatalf("invalid user data, expected %q and received %q", testUserData, userData)
+	}
+}
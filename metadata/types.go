@@ -0,0 +1,58 @@
+package metadata
+
+// Metadata represents the metadata of an UpCloud server
+type Metadata struct {
+	CloudName   string   `json:"cloud_name"`
+	InstanceID  string   `json:"instance_id"`
+	Hostname    string   `json:"hostname"`
+	Platform    string   `json:"platform"`
+	Subplatform string   `json:"subplatform"`
+	Region      string   `json:"region"`
+	PublicKeys  []string `json:"public_keys"`
+	Tags        []string `json:"tags"`
+	UserData    string   `json:"user_data"`
+	VendorData  string   `json:"vendor_data"`
+	Network     Network  `json:"network"`
+	Storage     Storage  `json:"storage"`
+}
+
+// Network represents the network configuration of an UpCloud server
+type Network struct {
+	Interfaces []Interface `json:"interfaces"`
+	DNS        []string    `json:"dns"`
+}
+
+// Interface represents a network interface of an UpCloud server
+type Interface struct {
+	Index       int         `json:"index"`
+	IPAddresses []IPAddress `json:"ip_addresses"`
+	MAC         string      `json:"mac"`
+	NetworkID   string      `json:"network_id"`
+	Type        string      `json:"type"`
+}
+
+// IPAddress represents an IP address of a network interface
+type IPAddress struct {
+	Address  string   `json:"address"`
+	DHCP     bool     `json:"dhcp"`
+	DNS      []string `json:"dns"`
+	Family   string   `json:"family"`
+	Floating bool     `json:"floating"`
+	Gateway  string   `json:"gateway"`
+	Network  string   `json:"network"`
+}
+
+// Storage represents the storage configuration of an UpCloud server
+type Storage struct {
+	Disks []Disk `json:"disks"`
+}
+
+// Disk represents a disk of an UpCloud server
+type Disk struct {
+	ID     string `json:"id"`
+	Serial string `json:"serial"`
+	// Size of the disk in MB
+	Size int    `json:"size"`
+	Type string `json:"type"`
+	Tier string `json:"tier"`
+}
@@ -0,0 +1,73 @@
+package upcloud
+
+import (
+	"net/http"
+
+	"github.com/hatchify/requester"
+)
+
+// Request represents a single API request passing through the middleware chain
+type Request struct {
+	// Operation is the name of the SDK method (e.g. "CreateServer")
+	Operation string
+	Method    string
+	// Endpoint is the path relative to the API version (e.g. "server/<uuid>/stop")
+	Endpoint string
+	Body     []byte
+	// Opts are applied to the HTTP request in order, later options take precedence
+	Opts requester.Opts
+}
+
+// RoundTrip sends a request and returns the response
+type RoundTrip func(req *Request) (*http.Response, error)
+
+// Middleware wraps a RoundTrip, calling next to continue the chain
+// Note: A middleware may short-circuit the chain by returning without calling next
+type Middleware func(next RoundTrip) RoundTrip
+
+// Use will append middlewares to the chain of every request
+// Note: The BasicAuth and Headers middlewares are installed by New, middlewares added afterwards
+// run inside of them and their options are applied after (e.g. to override a header)
+func (u *UpCloud) Use(middlewares ...Middleware) {
+	u.middlewares = append(u.middlewares, middlewares...)
+}
+
+// BasicAuth will return a middleware which authenticates requests with the provided credentials
+func BasicAuth(username, password string) Middleware {
+	var setBasicAuth requester.Modifier = func(request *http.Request, client *http.Client) (err error) {
+		request.SetBasicAuth(username, password)
+		return
+	}
+
+	return func(next RoundTrip) RoundTrip {
+		return func(req *Request) (*http.Response, error) {
+			req.Opts = append(req.Opts, setBasicAuth)
+			return next(req)
+		}
+	}
+}
+
+// Headers will return a middleware which sets the provided headers on requests
+func Headers(headers ...requester.Header) Middleware {
+	var setHeaders = requester.NewHeaders(headers...)
+	return func(next RoundTrip) RoundTrip {
+		return func(req *Request) (*http.Response, error) {
+			req.Opts = append(req.Opts, setHeaders)
+			return next(req)
+		}
+	}
+}
+
+// chain will return the round trip wrapped with every middleware, the first middleware being outermost
+func (u *UpCloud) chain(rt RoundTrip) RoundTrip {
+	// The circuit breaker is innermost, so each attempt of retrying middlewares is recorded
+	if u.circuitBreaker != nil {
+		rt = u.circuitBreaker.Middleware()(rt)
+	}
+
+	for i := len(u.middlewares) - 1; i >= 0; i-- {
+		rt = u.middlewares[i](rt)
+	}
+
+	return rt
+}
@@ -0,0 +1,79 @@
+package upcloud
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hatchify/requester"
+)
+
+func TestUpCloud_Use(t *testing.T) {
+	var err error
+	var received http.Header
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte(`{"account":{"username":"username"}}`))
+	}))
+	defer srv.Close()
+
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, srv.URL))
+
+	var operations []string
+	u.Use(func(next RoundTrip) RoundTrip {
+		return func(req *Request) (*http.Response, error) {
+			operations = append(operations, req.Operation)
+			return next(req)
+		}
+	}, Headers(requester.Header{Key: "X-Audit-ID", Val: "42"}))
+
+	if _, err = u.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(operations) != 1 || operations[0] != "GetAccount" {
+		t.Fatalf("invalid operations, received %v", operations)
+	}
+
+	if username, password, ok := (&http.Request{Header: received}).BasicAuth(); !ok || username != "username" || password != "password" {
+		t.Fatalf("invalid basic auth, received \"%s\" and \"%s\"", username, password)
+	}
+
+	if received.Get("X-Audit-ID") != "42" || received.Get("Content-Type") != "application/json" {
+		t.Fatalf("invalid headers, received %v", received)
+	}
+}
+
+func TestUpCloud_UseShortCircuit(t *testing.T) {
+	var err error
+	var u *UpCloud
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(requester.New(&http.Client{}, "http://127.0.0.1:0"))
+	u.Use(func(next RoundTrip) RoundTrip {
+		return func(req *Request) (*http.Response, error) {
+			var res http.Response
+			res.StatusCode = http.StatusOK
+			res.Body = ioutil.NopCloser(bytes.NewBufferString(`{"account":{"username":"cached"}}`))
+			return &res, nil
+		}
+	})
+
+	var a *Account
+	if a, err = u.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Username != "cached" {
+		t.Fatalf("invalid username, expected \"%s\" and received \"%s\"", "cached", a.Username)
+	}
+}
@@ -0,0 +1,68 @@
+package upcloud
+
+// Network represents an UpCloud network
+type Network struct {
+	IPNetworks *IPNetworks `json:"ip_networks,omitempty"`
+	Name       string      `json:"name"`
+	// Type of the network: public, utility or private
+	Type string `json:"type"`
+	UUID string `json:"uuid"`
+	Zone string `json:"zone"`
+}
+
+// Networks represents a list of networks
+type Networks struct {
+	Network *[]Network `json:"network"`
+}
+
+// List will return the networks as a slice
+// Note: A nil slice is returned when the value or networks are not set
+func (n *Networks) List() []Network {
+	if n == nil || n.Network == nil {
+		return nil
+	}
+
+	return *n.Network
+}
+
+// IPNetwork represents an IP address range of a network
+type IPNetwork struct {
+	// Address range in CIDR notation (e.g. "10.0.0.0/24")
+	Address string          `json:"address"`
+	DHCP    YesNo           `json:"dhcp"`
+	Family  IPAddressFamily `json:"family"`
+	Gateway string          `json:"gateway,omitempty"`
+}
+
+// IPNetworks represents a list of IP networks
+type IPNetworks struct {
+	IPNetwork *[]IPNetwork `json:"ip_network"`
+}
+
+// List will return the IP networks as a slice
+// Note: A nil slice is returned when the value or IP networks are not set
+func (i *IPNetworks) List() []IPNetwork {
+	if i == nil || i.IPNetwork == nil {
+		return nil
+	}
+
+	return *i.IPNetwork
+}
+
+type getNetworksResponse struct {
+	Networks *Networks `json:"networks"`
+}
+
+// GetNetworks gets all the networks available to the account
+func (u *UpCloud) GetNetworks() (p *[]Network, err error) {
+	var resp getNetworksResponse
+	// Make request to "Get Networks" route
+	if err = u.request("GetNetworks", "GET", RouteNetwork, nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	networks := resp.Networks.List()
+	p = &networks
+	return
+}
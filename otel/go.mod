@@ -0,0 +1,22 @@
+module github.com/hatchify/upcloud-sdk/otel
+
+go 1.21
+
+require (
+	github.com/hatchify/upcloud-sdk v0.0.0-20261019134621-1a74e573000b
+	go.opentelemetry.io/otel v1.21.0
+	go.opentelemetry.io/otel/metric v1.21.0
+	go.opentelemetry.io/otel/sdk v1.21.0
+	go.opentelemetry.io/otel/sdk/metric v1.21.0
+	go.opentelemetry.io/otel/trace v1.21.0
+)
+
+require (
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	golang.org/x/sys v0.14.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Local development builds against the SDK within this repository, consumers use the required version
+replace github.com/hatchify/upcloud-sdk => ../
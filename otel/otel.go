@@ -0,0 +1,159 @@
+// Package upcloudotel provides OpenTelemetry implementations of the UpCloud SDK
+// Tracer and Metrics interfaces
+//
+// It lives in its own module so the SDK itself does not depend on OpenTelemetry:
+//
+//	u.SetTracer(upcloudotel.NewTracer(otel.Tracer("upcloud")))
+//	metrics, err := upcloudotel.NewMetrics(otel.Meter("upcloud"))
+//	u.SetMetrics(metrics)
+package upcloudotel
+
+import (
+	"context"
+	"fmt"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MetricRequests is the name of the request counter
+	MetricRequests = "upcloud.requests"
+	// MetricErrors is the name of the error counter
+	MetricErrors = "upcloud.errors"
+	// MetricDuration is the name of the request latency histogram
+	MetricDuration = "upcloud.request.duration"
+)
+
+// attributeOperation is the metric attribute key of the name of the SDK method
+const attributeOperation = "upcloud.operation"
+
+var _ upcloud.Tracer = &Tracer{}
+var _ upcloud.Metrics = &Metrics{}
+
+// NewTracer will return a new tracer which starts client spans with the provided tracer
+func NewTracer(tracer trace.Tracer) *Tracer {
+	var t Tracer
+	t.tracer = tracer
+	t.ctx = context.Background()
+	return &t
+}
+
+// NewTracerWithContext will return a new tracer which starts spans as children of the span within the context
+// Note: This is useful to attach SDK calls to the span of an inbound request
+func NewTracerWithContext(ctx context.Context, tracer trace.Tracer) *Tracer {
+	var t Tracer
+	t.tracer = tracer
+	t.ctx = ctx
+	return &t
+}
+
+// Tracer is an OpenTelemetry implementation of upcloud.Tracer
+type Tracer struct {
+	tracer trace.Tracer
+	ctx    context.Context
+}
+
+// StartSpan will start a new client span
+func (t *Tracer) StartSpan(name string) upcloud.Span {
+	_, span := t.tracer.Start(t.ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return &Span{span: span}
+}
+
+// Span is an OpenTelemetry implementation of upcloud.Span
+type Span struct {
+	span trace.Span
+}
+
+// SetAttribute will set an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.span.SetAttributes(toAttribute(key, value))
+}
+
+// End will end the span, recording the error when set
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
+
+// NewMetrics will return a new metrics recorder using instruments created with the provided meter
+func NewMetrics(meter metric.Meter) (mp *Metrics, err error) {
+	var m Metrics
+	if m.requests, err = meter.Int64Counter(MetricRequests,
+		metric.WithDescription("Number of UpCloud API requests"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return
+	}
+
+	if m.errors, err = meter.Int64Counter(MetricErrors,
+		metric.WithDescription("Number of failed UpCloud API requests"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return
+	}
+
+	if m.duration, err = meter.Float64Histogram(MetricDuration,
+		metric.WithDescription("Latency of UpCloud API requests"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return
+	}
+
+	mp = &m
+	return
+}
+
+// Metrics is an OpenTelemetry implementation of upcloud.Metrics
+type Metrics struct {
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// RecordRequest will record the request count, latency and error code of a request
+func (m *Metrics) RecordRequest(r upcloud.RequestMetric) {
+	var ctx = context.Background()
+	var attrs = []attribute.KeyValue{
+		attribute.String(attributeOperation, r.Operation),
+		attribute.String(upcloud.AttributeMethod, r.Method),
+		attribute.Int(upcloud.AttributeStatusCode, r.StatusCode),
+	}
+
+	m.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	m.duration.Record(ctx, r.Latency.Seconds(), metric.WithAttributes(attrs...))
+	if r.Err == nil {
+		return
+	}
+
+	var errorCode = r.ErrorCode
+	if errorCode == "" {
+		// Transport and decoding errors have no UpCloud error code
+		errorCode = "unknown"
+	}
+
+	attrs = append(attrs, attribute.String(upcloud.AttributeErrorCode, errorCode))
+	m.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
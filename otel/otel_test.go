@@ -0,0 +1,85 @@
+package upcloudotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	span := NewTracer(provider.Tracer("upcloud")).StartSpan("upcloud.GetAccount")
+	span.SetAttribute(upcloud.AttributeZone, "fi-hel1")
+	span.End(errors.New("service unavailable"))
+
+	var spans = recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("invalid number of spans, expected %d and received %d", 1, len(spans))
+	}
+
+	var s = spans[0]
+	if s.Name() != "upcloud.GetAccount" || s.SpanKind() != trace.SpanKindClient {
+		t.Fatalf("invalid span, received %s of kind %s", s.Name(), s.SpanKind())
+	}
+
+	if s.Status().Code != codes.Error {
+		t.Fatalf("invalid status, expected %s and received %s", codes.Error, s.Status().Code)
+	}
+
+	var expected = attribute.String(upcloud.AttributeZone, "fi-hel1")
+	if attrs := s.Attributes(); len(attrs) != 1 || attrs[0] != expected {
+		t.Fatalf("invalid attributes, expected %v and received %v", expected, attrs)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	var err error
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	var m *Metrics
+	if m, err = NewMetrics(provider.Meter("upcloud")); err != nil {
+		t.Fatal(err)
+	}
+
+	m.RecordRequest(upcloud.RequestMetric{Operation: "GetAccount", Method: "GET", StatusCode: 200, Latency: time.Millisecond})
+	m.RecordRequest(upcloud.RequestMetric{Operation: "GetAccount", Method: "GET", StatusCode: 503, Latency: time.Millisecond, Err: errors.New("service unavailable")})
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	var sums = make(map[string]int64)
+	var histograms = make(map[string]uint64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, metric := range sm.Metrics {
+			switch data := metric.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					sums[metric.Name] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					histograms[metric.Name] += point.Count
+				}
+			}
+		}
+	}
+
+	if sums[MetricRequests] != 2 || sums[MetricErrors] != 1 || histograms[MetricDuration] != 2 {
+		t.Fatalf("invalid metrics, received sums %v and histograms %v", sums, histograms)
+	}
+}
@@ -0,0 +1,431 @@
+package plain
+
+import (
+	"encoding/json"
+
+	v1 "github.com/hatchify/upcloud-sdk"
+)
+
+// Models without nested lists are shared with v1
+type (
+	Account       = v1.Account
+	Zone          = v1.Zone
+	Plan          = v1.Plan
+	ServerSize    = v1.ServerSize
+	Storage       = v1.Storage
+	PriceZone     = v1.PriceZone
+	Price         = v1.Price
+	CostEstimate  = v1.CostEstimate
+	CostItem      = v1.CostItem
+	IPAddress     = v1.IPAddress
+	StorageDevice = v1.StorageDevice
+	StartServer   = v1.StartServer
+	StopServer    = v1.StopServer
+	DeleteServer  = v1.DeleteServer
+	CreateStorage = v1.CreateStorage
+	ListOptions   = v1.ListOptions
+	TemplateQuery = v1.TemplateQuery
+	Error         = v1.Error
+	FirewallRule  = v1.FirewallRule
+	IPNetwork     = v1.IPNetwork
+
+	YesNo       = v1.YesNo
+	OnOff       = v1.OnOff
+	StringInt   = v1.StringInt
+	StringFloat = v1.StringFloat
+
+	ServerState     = v1.ServerState
+	StorageState    = v1.StorageState
+	StorageType     = v1.StorageType
+	StorageTier     = v1.StorageTier
+	StorageAccess   = v1.StorageAccess
+	IPAddressFamily = v1.IPAddressFamily
+	BackupPolicy    = v1.BackupPolicy
+
+	RouteGetStorageFilter = v1.RouteGetStorageFilter
+
+	Logger        = v1.Logger
+	Tracer        = v1.Tracer
+	Span          = v1.Span
+	Metrics       = v1.Metrics
+	RequestMetric = v1.RequestMetric
+	Request       = v1.Request
+	RoundTrip     = v1.RoundTrip
+	Middleware    = v1.Middleware
+
+	CircuitBreaker        = v1.CircuitBreaker
+	CircuitBreakerOptions = v1.CircuitBreakerOptions
+	CircuitState          = v1.CircuitState
+
+	BulkOptions = v1.BulkOptions
+	BulkResult  = v1.BulkResult
+	BulkError   = v1.BulkError
+
+	Spec            = v1.Spec
+	ServerSpec      = v1.ServerSpec
+	DiskSpec        = v1.DiskSpec
+	NetworkSpec     = v1.NetworkSpec
+	ReconcilePlan   = v1.ReconcilePlan
+	ReconcileChange = v1.ReconcileChange
+	ReconcileAction = v1.ReconcileAction
+
+	// Account states are an export format, they keep the v1 models so exports stay compatible
+	AccountState    = v1.AccountState
+	ServerExport    = v1.ServerExport
+	IPAddressExport = v1.IPAddressExport
+	TagExport       = v1.TagExport
+	StateChange     = v1.StateChange
+	ChangeType      = v1.ChangeType
+)
+
+const (
+	All      = v1.All
+	Public   = v1.Public
+	Private  = v1.Private
+	Normal   = v1.Normal
+	Backup   = v1.Backup
+	Cdrom    = v1.Cdrom
+	Template = v1.Template
+	Favorite = v1.Favorite
+)
+
+// Server represents UpCloud server
+// Note: JSON is converted through the v1 model, so it matches the API format rather than the fields below
+type Server struct {
+	CoreNumber    StringInt   `json:"core_number,omitempty"`
+	Hostname      string      `json:"hostname,omitempty"`
+	License       int         `json:"license,omitempty"`
+	MemoryAmount  StringInt   `json:"memory_amount,omitempty"`
+	Plan          string      `json:"plan,omitempty"`
+	PlanIpv4Bytes StringInt   `json:"plan_ipv4_bytes,omitempty"`
+	PlanIpv6Bytes StringInt   `json:"plan_ipv6_bytes,omitempty"`
+	State         ServerState `json:"state,omitempty"`
+	Tags          []string    `json:"tags,omitempty"`
+	Title         string      `json:"title,omitempty"`
+	UUID          string      `json:"uuid,omitempty"`
+	Zone          string      `json:"zone,omitempty"`
+}
+
+// FromV1Server will convert a v1 server
+func FromV1Server(s v1.Server) (out Server) {
+	out.CoreNumber = s.CoreNumber
+	out.Hostname = s.Hostname
+	out.License = s.License
+	out.MemoryAmount = s.MemoryAmount
+	out.Plan = s.Plan
+	out.PlanIpv4Bytes = s.PlanIvp4Bytes
+	out.PlanIpv6Bytes = s.PlanIpv6Bytes
+	out.State = s.State
+	out.Tags = s.Tags.List()
+	out.Title = s.Title
+	out.UUID = s.UUID
+	out.Zone = s.Zone
+	return
+}
+
+// V1 will convert the server to a v1 server
+func (s Server) V1() (out v1.Server) {
+	out.CoreNumber = s.CoreNumber
+	out.Hostname = s.Hostname
+	out.License = s.License
+	out.MemoryAmount = s.MemoryAmount
+	out.Plan = s.Plan
+	out.PlanIvp4Bytes = s.PlanIpv4Bytes
+	out.PlanIpv6Bytes = s.PlanIpv6Bytes
+	out.State = s.State
+	if s.Tags != nil {
+		out.Tags = &v1.Tags{Tag: copyStrings(s.Tags)}
+	}
+
+	out.Title = s.Title
+	out.UUID = s.UUID
+	out.Zone = s.Zone
+	return
+}
+
+// MarshalJSON will marshal the server in the API format (e.g. {"tags":{"tag":[...]}})
+func (s Server) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.V1())
+}
+
+// UnmarshalJSON will unmarshal a server in the API format
+func (s *Server) UnmarshalJSON(bs []byte) (err error) {
+	var server v1.Server
+	if err = json.Unmarshal(bs, &server); err != nil {
+		return
+	}
+
+	*s = FromV1Server(server)
+	return
+}
+
+// Network represents an UpCloud network
+// Note: JSON is converted through the v1 model, so it matches the API format rather than the fields below
+type Network struct {
+	IPNetworks []IPNetwork `json:"ip_networks,omitempty"`
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	UUID       string      `json:"uuid"`
+	Zone       string      `json:"zone"`
+}
+
+// FromV1Network will convert a v1 network
+func FromV1Network(n v1.Network) (out Network) {
+	out.IPNetworks = n.IPNetworks.List()
+	out.Name = n.Name
+	out.Type = n.Type
+	out.UUID = n.UUID
+	out.Zone = n.Zone
+	return
+}
+
+// V1 will convert the network to a v1 network
+func (n Network) V1() (out v1.Network) {
+	if n.IPNetworks != nil {
+		var ipNetworks = append([]IPNetwork{}, n.IPNetworks...)
+		out.IPNetworks = &v1.IPNetworks{IPNetwork: &ipNetworks}
+	}
+
+	out.Name = n.Name
+	out.Type = n.Type
+	out.UUID = n.UUID
+	out.Zone = n.Zone
+	return
+}
+
+// MarshalJSON will marshal the network in the API format (e.g. {"ip_networks":{"ip_network":[...]}})
+func (n Network) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.V1())
+}
+
+// UnmarshalJSON will unmarshal a network in the API format
+func (n *Network) UnmarshalJSON(bs []byte) (err error) {
+	var network v1.Network
+	if err = json.Unmarshal(bs, &network); err != nil {
+		return
+	}
+
+	*n = FromV1Network(network)
+	return
+}
+
+// Interface represents a network interface of an UpCloud server
+type Interface struct {
+	Index             int         `json:"index,omitempty"`
+	IPAddresses       []IPAddress `json:"ip_addresses,omitempty"`
+	Mac               string      `json:"mac,omitempty"`
+	Network           string      `json:"network,omitempty"`
+	Type              string      `json:"type,omitempty"`
+	Bootable          *YesNo      `json:"bootable,omitempty"`
+	SourceIPFiltering *YesNo      `json:"source_ip_filtering,omitempty"`
+}
+
+// LoginUser represents the user created during server creation
+type LoginUser struct {
+	Username       string   `json:"username,omitempty"`
+	CreatePassword *YesNo   `json:"create_password,omitempty"`
+	SSHKeys        []string `json:"ssh_keys,omitempty"`
+}
+
+// ServerDetails represents all UpCloud detailed server objects
+// Note: JSON is converted through the v1 model, so it matches the API format rather than the fields below
+type ServerDetails struct {
+	BootOrder            string          `json:"boot_order,omitempty"`
+	CoreNumber           StringInt       `json:"core_number,omitempty"`
+	Firewall             *OnOff          `json:"firewall,omitempty"`
+	Host                 int64           `json:"host,omitempty"`
+	Hostname             string          `json:"hostname,omitempty"`
+	IPAddresses          []IPAddress     `json:"ip_addresses,omitempty"`
+	LoginUser            *LoginUser      `json:"login_user,omitempty"`
+	License              int             `json:"license,omitempty"`
+	MemoryAmount         StringInt       `json:"memory_amount,omitempty"`
+	Metadata             *YesNo          `json:"metadata,omitempty"`
+	Interfaces           []Interface     `json:"interfaces,omitempty"`
+	NicModel             string          `json:"nic_model,omitempty"`
+	Password             string          `json:"password,omitempty"`
+	Plan                 string          `json:"plan,omitempty"`
+	PlanIpv4Bytes        StringInt       `json:"plan_ipv4_bytes,omitempty"`
+	PlanIpv6Bytes        StringInt       `json:"plan_ipv6_bytes,omitempty"`
+	SimpleBackup         string          `json:"simple_backup,omitempty"`
+	State                ServerState     `json:"state,omitempty"`
+	StorageDevices       []StorageDevice `json:"storage_devices,omitempty"`
+	Tags                 []string        `json:"tags,omitempty"`
+	Timezone             string          `json:"timezone,omitempty"`
+	Title                string          `json:"title,omitempty"`
+	UserData             string          `json:"user_data,omitempty"`
+	UUID                 string          `json:"uuid,omitempty"`
+	VideoModel           string          `json:"video_model,omitempty"`
+	RemoteAccessEnabled  *YesNo          `json:"remote_access_enabled,omitempty"`
+	RemoteAccessType     string          `json:"remote_access_type,omitempty"`
+	RemoteAccessHost     string          `json:"remote_access_host,omitempty"`
+	RemoteAccessPassword string          `json:"remote_access_password,omitempty"`
+	RemoteAccessPort     StringInt       `json:"remote_access_port,omitempty"`
+	Zone                 string          `json:"zone,omitempty"`
+}
+
+// FromV1ServerDetails will convert v1 server details
+// Note: Nil server details are converted to nil
+func FromV1ServerDetails(sd *v1.ServerDetails) *ServerDetails {
+	if sd == nil {
+		return nil
+	}
+
+	var out ServerDetails
+	out.BootOrder = sd.BootOrder
+	out.CoreNumber = sd.CoreNumber
+	out.Firewall = sd.Firewall
+	out.Host = sd.Host
+	out.Hostname = sd.Hostname
+	out.IPAddresses = sd.IPAddresses.List()
+	if sd.LoginUser != nil {
+		out.LoginUser = &LoginUser{
+			Username:       sd.LoginUser.Username,
+			CreatePassword: sd.LoginUser.CreatePassword,
+			SSHKeys:        sd.LoginUser.SSHKeys.List(),
+		}
+	}
+
+	out.License = sd.License
+	out.MemoryAmount = sd.MemoryAmount
+	out.Metadata = sd.Metadata
+	for _, iface := range sd.Networking.InterfaceList() {
+		out.Interfaces = append(out.Interfaces, Interface{
+			Index:             iface.Index,
+			IPAddresses:       iface.IPAddresses.List(),
+			Mac:               iface.Mac,
+			Network:           iface.Network,
+			Type:              iface.Type,
+			Bootable:          iface.Bootable,
+			SourceIPFiltering: iface.SourceIPFiltering,
+		})
+	}
+
+	out.NicModel = sd.NicModel
+	out.Password = sd.Password
+	out.Plan = sd.Plan
+	out.PlanIpv4Bytes = sd.PlanIpv4Bytes
+	out.PlanIpv6Bytes = sd.PlanIpv6Bytes
+	out.SimpleBackup = sd.SimpleBackup
+	out.State = sd.State
+	out.StorageDevices = sd.StorageDevices.List()
+	out.Tags = sd.Tags.List()
+	out.Timezone = sd.Timezone
+	out.Title = sd.Title
+	out.UserData = sd.UserData
+	out.UUID = sd.UUID
+	out.VideoModel = sd.VideoModel
+	out.RemoteAccessEnabled = sd.RemoteAccessEnabled
+	out.RemoteAccessType = sd.RemoteAccessType
+	out.RemoteAccessHost = sd.RemoteAccessHost
+	out.RemoteAccessPassword = sd.RemoteAccessPassword
+	out.RemoteAccessPort = sd.RemoteAccessPort
+	out.Zone = sd.Zone
+	return &out
+}
+
+// V1 will convert the server details to v1 server details
+// Note: Nil slices are omitted while empty slices are sent (e.g. to clear all tags)
+func (s *ServerDetails) V1() *v1.ServerDetails {
+	if s == nil {
+		return nil
+	}
+
+	var out v1.ServerDetails
+	out.BootOrder = s.BootOrder
+	out.CoreNumber = s.CoreNumber
+	out.Firewall = s.Firewall
+	out.Host = s.Host
+	out.Hostname = s.Hostname
+	if s.IPAddresses != nil {
+		out.IPAddresses = &v1.IPAddresses{IPAddress: copyIPAddresses(s.IPAddresses)}
+	}
+
+	if s.LoginUser != nil {
+		out.LoginUser = &v1.LoginUser{
+			Username:       s.LoginUser.Username,
+			CreatePassword: s.LoginUser.CreatePassword,
+		}
+
+		if s.LoginUser.SSHKeys != nil {
+			out.LoginUser.SSHKeys = &v1.SSHKeys{SSHKey: copyStrings(s.LoginUser.SSHKeys)}
+		}
+	}
+
+	out.License = s.License
+	out.MemoryAmount = s.MemoryAmount
+	out.Metadata = s.Metadata
+	if s.Interfaces != nil {
+		var interfaces = make([]v1.Interface, 0, len(s.Interfaces))
+		for _, iface := range s.Interfaces {
+			var v1Interface v1.Interface
+			v1Interface.Index = iface.Index
+			if iface.IPAddresses != nil {
+				v1Interface.IPAddresses = &v1.IPAddresses{IPAddress: copyIPAddresses(iface.IPAddresses)}
+			}
+
+			v1Interface.Mac = iface.Mac
+			v1Interface.Network = iface.Network
+			v1Interface.Type = iface.Type
+			v1Interface.Bootable = iface.Bootable
+			v1Interface.SourceIPFiltering = iface.SourceIPFiltering
+			interfaces = append(interfaces, v1Interface)
+		}
+
+		out.Networking = &v1.Networking{Interfaces: &v1.Interfaces{Interface: &interfaces}}
+	}
+
+	out.NicModel = s.NicModel
+	out.Password = s.Password
+	out.Plan = s.Plan
+	out.PlanIpv4Bytes = s.PlanIpv4Bytes
+	out.PlanIpv6Bytes = s.PlanIpv6Bytes
+	out.SimpleBackup = s.SimpleBackup
+	out.State = s.State
+	if s.StorageDevices != nil {
+		var storageDevices = append([]StorageDevice{}, s.StorageDevices...)
+		out.StorageDevices = &v1.StorageDevices{StorageDevice: &storageDevices}
+	}
+
+	if s.Tags != nil {
+		out.Tags = &v1.Tags{Tag: copyStrings(s.Tags)}
+	}
+
+	out.Timezone = s.Timezone
+	out.Title = s.Title
+	out.UserData = s.UserData
+	out.UUID = s.UUID
+	out.VideoModel = s.VideoModel
+	out.RemoteAccessEnabled = s.RemoteAccessEnabled
+	out.RemoteAccessType = s.RemoteAccessType
+	out.RemoteAccessHost = s.RemoteAccessHost
+	out.RemoteAccessPassword = s.RemoteAccessPassword
+	out.RemoteAccessPort = s.RemoteAccessPort
+	out.Zone = s.Zone
+	return &out
+}
+
+// MarshalJSON will marshal the server details in the API format (e.g. {"tags":{"tag":[...]}})
+func (s ServerDetails) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.V1())
+}
+
+// UnmarshalJSON will unmarshal server details in the API format
+func (s *ServerDetails) UnmarshalJSON(bs []byte) (err error) {
+	var sd v1.ServerDetails
+	if err = json.Unmarshal(bs, &sd); err != nil {
+		return
+	}
+
+	*s = *FromV1ServerDetails(&sd)
+	return
+}
+
+func copyStrings(in []string) *[]string {
+	var out = append([]string{}, in...)
+	return &out
+}
+
+func copyIPAddresses(in []IPAddress) *[]IPAddress {
+	var out = append([]IPAddress{}, in...)
+	return &out
+}
@@ -0,0 +1,354 @@
+// Package plain is an alternative API surface of the UpCloud SDK
+//
+// Lists are returned as plain slices and models use plain slices instead of
+// pointers to wrapped slices, so missing fields are safe to range over. The plain
+// client wraps the v1 client, use Wrap and V1 to migrate code incrementally.
+package plain
+
+import (
+	"context"
+	"time"
+
+	"github.com/hatchify/requester"
+	v1 "github.com/hatchify/upcloud-sdk"
+)
+
+// New will return a new instance of the UpCloud API SDK
+func New(username, password string) (up *UpCloud, err error) {
+	var u *v1.UpCloud
+	if u, err = v1.New(username, password); err != nil {
+		return
+	}
+
+	up = Wrap(u)
+	return
+}
+
+// Wrap will return a plain client for an existing v1 client
+// Note: Both clients share the same credentials and requester
+func Wrap(u *v1.UpCloud) *UpCloud {
+	return &UpCloud{u: u}
+}
+
+// UpCloud manages requests to the UpCloud API
+type UpCloud struct {
+	u *v1.UpCloud
+}
+
+// V1 will return the underlying v1 client
+func (u *UpCloud) V1() *v1.UpCloud {
+	return u.u
+}
+
+// SetRequester will set the requester used for API requests
+func (u *UpCloud) SetRequester(newReq requester.Interface) {
+	u.u.SetRequester(newReq)
+}
+
+// SetLogger will set the logger used for requests
+func (u *UpCloud) SetLogger(logger Logger) {
+	u.u.SetLogger(logger)
+}
+
+// SetDebug will enable or disable debug logging of every request along with redacted bodies
+func (u *UpCloud) SetDebug(debug bool) {
+	u.u.SetDebug(debug)
+}
+
+// Use will append middlewares to the chain of every request
+func (u *UpCloud) Use(middlewares ...Middleware) {
+	u.u.Use(middlewares...)
+}
+
+// SetCircuitBreaker will protect requests with the provided circuit breaker, nil disables it
+func (u *UpCloud) SetCircuitBreaker(c *CircuitBreaker) {
+	u.u.SetCircuitBreaker(c)
+}
+
+// SetTracer will set the tracer used for requests
+func (u *UpCloud) SetTracer(tracer Tracer) {
+	u.u.SetTracer(tracer)
+}
+
+// SetMetrics will set the metrics recorder used for requests
+func (u *UpCloud) SetMetrics(metrics Metrics) {
+	u.u.SetMetrics(metrics)
+}
+
+// GetAccount will get the account of the currently logged in user
+func (u *UpCloud) GetAccount() (a *Account, err error) {
+	return u.u.GetAccount()
+}
+
+// GetZones gets all the regions/zones
+func (u *UpCloud) GetZones() (z []Zone, err error) {
+	var zones *[]Zone
+	if zones, err = u.u.GetZones(); err != nil {
+		return
+	}
+
+	z = *zones
+	return
+}
+
+// GetZone gets a zone by its ID
+func (u *UpCloud) GetZone(id string) (z *Zone, err error) {
+	return u.u.GetZone(id)
+}
+
+// ZonesByCountry gets all the zones grouped by their country code (e.g. "de", "fi", "us")
+func (u *UpCloud) ZonesByCountry() (zc map[string][]Zone, err error) {
+	return u.u.ZonesByCountry()
+}
+
+// GetPlans gets all the plans available
+func (u *UpCloud) GetPlans() (p []Plan, err error) {
+	var plans *[]Plan
+	if plans, err = u.u.GetPlans(); err != nil {
+		return
+	}
+
+	p = *plans
+	return
+}
+
+// GetTimezones gets all the timezones available for servers
+func (u *UpCloud) GetTimezones() (t []string, err error) {
+	var timezones *[]string
+	if timezones, err = u.u.GetTimezones(); err != nil {
+		return
+	}
+
+	t = *timezones
+	return
+}
+
+// GetPrices gets the price tables of all the zones
+func (u *UpCloud) GetPrices() (p []PriceZone, err error) {
+	var prices *[]PriceZone
+	if prices, err = u.u.GetPrices(); err != nil {
+		return
+	}
+
+	p = *prices
+	return
+}
+
+// GetServerSizes gets all the available server sizes
+func (u *UpCloud) GetServerSizes() (s []ServerSize, err error) {
+	var serverSizes *[]ServerSize
+	if serverSizes, err = u.u.GetServerSizes(); err != nil {
+		return
+	}
+
+	s = *serverSizes
+	return
+}
+
+// GetServers gets all the servers
+func (u *UpCloud) GetServers() (s []Server, err error) {
+	return u.GetServersWithOptions(ListOptions{})
+}
+
+// GetServersWithOptions gets the servers matching the provided list options
+func (u *UpCloud) GetServersWithOptions(options ListOptions) (s []Server, err error) {
+	var servers *[]v1.Server
+	if servers, err = u.u.GetServersWithOptions(options); err != nil {
+		return
+	}
+
+	s = make([]Server, 0, len(*servers))
+	for _, server := range *servers {
+		s = append(s, FromV1Server(server))
+	}
+
+	return
+}
+
+// GetServerDetails gets server details based on UUID
+func (u *UpCloud) GetServerDetails(uuid string) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.GetServerDetails(uuid))
+}
+
+// GetStorages gets all the storage options
+func (u *UpCloud) GetStorages(filter RouteGetStorageFilter) (s []Storage, err error) {
+	return u.GetStoragesWithOptions(filter, ListOptions{})
+}
+
+// GetStoragesWithOptions gets the storages matching the provided filter and list options
+func (u *UpCloud) GetStoragesWithOptions(filter RouteGetStorageFilter, options ListOptions) (s []Storage, err error) {
+	var storages *[]Storage
+	if storages, err = u.u.GetStoragesWithOptions(filter, options); err != nil {
+		return
+	}
+
+	s = *storages
+	return
+}
+
+// CreateServer creates a new server
+func (u *UpCloud) CreateServer(serverDetails *ServerDetails) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.CreateServer(serverDetails.V1()))
+}
+
+// ModifyServer modifies an already existing server
+// Note: Only the set fields of the provided server details are modified
+func (u *UpCloud) ModifyServer(uuid string, serverDetails *ServerDetails) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.ModifyServer(uuid, serverDetails.V1()))
+}
+
+// SetMetadata enables or disables the metadata service of an already existing server
+func (u *UpCloud) SetMetadata(uuid string, enabled bool) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.SetMetadata(uuid, enabled))
+}
+
+// TagServer assigns the provided tags to an already existing server
+// Note: Tags are not changed by ModifyServer, UpCloud assigns and removes them through dedicated calls
+func (u *UpCloud) TagServer(uuid string, tags ...string) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.TagServer(uuid, tags...))
+}
+
+// UntagServer removes the provided tags from an already existing server
+func (u *UpCloud) UntagServer(uuid string, tags ...string) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.UntagServer(uuid, tags...))
+}
+
+// StopServer stops an already existing server
+func (u *UpCloud) StopServer(uuid string, options StopServer) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.StopServer(uuid, options))
+}
+
+// StartServer starts an already existing server
+func (u *UpCloud) StartServer(uuid string, options StartServer) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.StartServer(uuid, options))
+}
+
+// DeleteServer deletes an already existing server
+func (u *UpCloud) DeleteServer(uuid string, deleteStorage bool) (err error) {
+	return u.u.DeleteServer(uuid, deleteStorage)
+}
+
+// DeleteServerWithOptions deletes an already existing server along with the selected storages
+func (u *UpCloud) DeleteServerWithOptions(ctx context.Context, uuid string, options DeleteServer) (err error) {
+	return u.u.DeleteServerWithOptions(ctx, uuid, options)
+}
+
+// WaitForState will poll the server details until the server is in the provided state
+func (u *UpCloud) WaitForState(ctx context.Context, uuid string, state ServerState) (s *ServerDetails, err error) {
+	return fromV1ServerDetails(u.u.WaitForState(ctx, uuid, state))
+}
+
+// SetPollInterval will set the interval between requests while waiting for a server state
+func (u *UpCloud) SetPollInterval(interval time.Duration) {
+	u.u.SetPollInterval(interval)
+}
+
+// ServerFilter will return whether or not a server is processed by ForEachServer
+type ServerFilter func(s *Server) bool
+
+// ServerFunc is called by ForEachServer for each server matching the filter
+type ServerFunc func(ctx context.Context, s Server) error
+
+// ForEachServer will call fn for every server matching the filter, with at most concurrency calls at once
+func (u *UpCloud) ForEachServer(ctx context.Context, filter ServerFilter, fn ServerFunc, concurrency int) (results []BulkResult, err error) {
+	var v1Filter v1.ServerFilter
+	if filter != nil {
+		v1Filter = func(s *v1.Server) bool {
+			var server = FromV1Server(*s)
+			return filter(&server)
+		}
+	}
+
+	return u.u.ForEachServer(ctx, v1Filter, func(ctx context.Context, s v1.Server) error {
+		return fn(ctx, FromV1Server(s))
+	}, concurrency)
+}
+
+// BulkStop will stop the servers
+func (u *UpCloud) BulkStop(ctx context.Context, uuids []string, stop StopServer, options BulkOptions) (results []BulkResult, err error) {
+	return u.u.BulkStop(ctx, uuids, stop, options)
+}
+
+// BulkStart will start the servers
+func (u *UpCloud) BulkStart(ctx context.Context, uuids []string, start StartServer, options BulkOptions) (results []BulkResult, err error) {
+	return u.u.BulkStart(ctx, uuids, start, options)
+}
+
+// BulkDelete will stop and delete the servers, along with their storages when deleteStorage is set
+func (u *UpCloud) BulkDelete(ctx context.Context, uuids []string, deleteStorage bool, options BulkOptions) (results []BulkResult, err error) {
+	return u.u.BulkDelete(ctx, uuids, deleteStorage, options)
+}
+
+// CreateStorage creates a new storage
+func (u *UpCloud) CreateStorage(options CreateStorage) (s *Storage, err error) {
+	return u.u.CreateStorage(options)
+}
+
+// DeleteStorage deletes an already existing storage
+func (u *UpCloud) DeleteStorage(uuid string) (err error) {
+	return u.u.DeleteStorage(uuid)
+}
+
+// EstimateServerCost will estimate the cost of running the provided server for the given amount of hours
+func (u *UpCloud) EstimateServerCost(serverDetails *ServerDetails, zone string, hours int) (e *CostEstimate, err error) {
+	return u.u.EstimateServerCost(serverDetails.V1(), zone, hours)
+}
+
+// FindTemplate will find the latest template matching the provided query for each zone
+func (u *UpCloud) FindTemplate(query TemplateQuery) (uuids map[string]string, err error) {
+	return u.u.FindTemplate(query)
+}
+
+// ResolveTemplate will return the UUID of the latest template of the OS family within the zone
+func (u *UpCloud) ResolveTemplate(template, zone string) (uuid string, err error) {
+	return u.u.ResolveTemplate(template, zone)
+}
+
+func fromV1ServerDetails(sd *v1.ServerDetails, err error) (*ServerDetails, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return FromV1ServerDetails(sd), nil
+}
+
+// PlanReconcile will return the changes required for the account to match the spec without applying them
+func (u *UpCloud) PlanReconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error) {
+	return u.u.PlanReconcile(ctx, spec)
+}
+
+// Reconcile will plan and apply the changes required for the account to match the spec
+func (u *UpCloud) Reconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error) {
+	return u.u.Reconcile(ctx, spec)
+}
+
+// GetFirewallRules gets the firewall rules of a server, in the order they are applied
+func (u *UpCloud) GetFirewallRules(uuid string) (f []FirewallRule, err error) {
+	var rules *[]FirewallRule
+	if rules, err = u.u.GetFirewallRules(uuid); err != nil {
+		return
+	}
+
+	f = *rules
+	return
+}
+
+// GetNetworks gets all the networks available to the account
+func (u *UpCloud) GetNetworks() (n []Network, err error) {
+	var networks *[]v1.Network
+	if networks, err = u.u.GetNetworks(); err != nil {
+		return
+	}
+
+	n = make([]Network, 0, len(*networks))
+	for _, network := range *networks {
+		n = append(n, FromV1Network(network))
+	}
+
+	return
+}
+
+// ExportAccountState will collect the servers, storages, networks, IP addresses and tags of the account into a single document
+func (u *UpCloud) ExportAccountState(ctx context.Context) (s *AccountState, err error) {
+	return u.u.ExportAccountState(ctx)
+}
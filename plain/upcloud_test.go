@@ -0,0 +1,116 @@
+package plain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hatchify/requester"
+	"github.com/hatchify/requester/mock"
+	v1 "github.com/hatchify/upcloud-sdk"
+)
+
+func setup(t *testing.T) (u *UpCloud) {
+	var err error
+	if u, err = New("username", "password"); err != nil {
+		t.Fatal("Couldn't create UpCloud object")
+	}
+
+	//Define what will be the backend for our mocks
+	var backend = mock.NewFileBackend("../testdata/new-backend.json")
+
+	//Setup Mock Requester
+	var r requester.Interface
+	if r, err = mock.NewRequester(v1.Hostname, backend); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(r)
+	return
+}
+
+func TestUpCloud_GetServers(t *testing.T) {
+	var err error
+	u := setup(t)
+
+	var servers []Server
+	if servers, err = u.GetServers(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range servers {
+		// Tags are safe to range over without nil checks
+		for _, tag := range s.Tags {
+			t.Log(s.UUID, tag)
+		}
+	}
+}
+
+func TestUpCloud_GetServerDetails(t *testing.T) {
+	var err error
+	u := setup(t)
+
+	var sd *ServerDetails
+	if sd, err = u.GetServerDetails("00334194-a6af-4fac-8eae-e098184c5e55"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sd.Interfaces) != 1 || sd.Interfaces[0].IPAddresses[0].Address != "209.50.53.216" {
+		t.Fatalf("invalid interfaces, received %+v", sd.Interfaces)
+	}
+
+	if len(sd.StorageDevices) != 1 {
+		t.Fatalf("invalid number of storage devices, expected %d and received %d", 1, len(sd.StorageDevices))
+	}
+}
+
+func TestServerDetails_V1(t *testing.T) {
+	var err error
+	var src = `{"hostname":"sdk-test-machine","login_user":{"username":"deploy","ssh_keys":{"ssh_key":["ssh-ed25519 AAAA"]}},"networking":{"interfaces":{"interface":[{"ip_addresses":{"ip_address":[{"family":"IPv4"}]},"type":"public"}]}},"storage_devices":{"storage_device":[{"action":"clone","storage":"01000000-0000-4000-8000-000030200200"}]},"tags":{"tag":[]},"zone":"us-chi1"}`
+
+	var sd v1.ServerDetails
+	if err = json.Unmarshal([]byte(src), &sd); err != nil {
+		t.Fatal(err)
+	}
+
+	var bs []byte
+	// Converting to plain models and back to v1 should not lose any information
+	if bs, err = json.Marshal(FromV1ServerDetails(&sd).V1()); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bs) != src {
+		t.Fatalf("invalid round trip, expected %s and received %s", src, bs)
+	}
+}
+
+func TestServerDetails_JSON(t *testing.T) {
+	var err error
+	var src = `{"hostname":"sdk-test-machine","networking":{"interfaces":{"interface":[{"ip_addresses":{"ip_address":[{"address":"209.50.53.216","family":"IPv4"}]},"type":"public"}]}},"storage_devices":{"storage_device":[{"storage":"01000000-0000-4000-8000-000030200200"}]},"tags":{"tag":["web"]},"zone":"us-chi1"}`
+
+	var sd ServerDetails
+	if err = json.Unmarshal([]byte(src), &sd); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sd.Tags) != 1 || len(sd.Interfaces) != 1 || len(sd.StorageDevices) != 1 {
+		t.Fatalf("invalid server details, expected the nested lists and received %+v", sd)
+	}
+
+	var bs []byte
+	if bs, err = json.Marshal(sd); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bs) != src {
+		t.Fatalf("invalid round trip, expected %s and received %s", src, bs)
+	}
+
+	var servers []Server
+	if err = json.Unmarshal([]byte(`[{"tags":{"tag":["web"]},"uuid":"1"}]`), &servers); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(servers) != 1 || len(servers[0].Tags) != 1 {
+		t.Fatalf("invalid servers, expected a single server with a tag and received %+v", servers)
+	}
+}
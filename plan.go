@@ -2,12 +2,12 @@ package upcloud
 
 // Plan represents UpCloud plan
 type Plan struct {
-	CoreNumber       int    `json:"core_number"`
-	MemoryAmount     int    `json:"memory_amount"`
-	Name             string `json:"name"`
-	PublicTrafficOut int    `json:"public_traffic_out"`
-	StorageSize      int    `json:"storage_size"`
-	StorageTier      string `json:"storage_tier"`
+	CoreNumber       int         `json:"core_number"`
+	MemoryAmount     int         `json:"memory_amount"`
+	Name             string      `json:"name"`
+	PublicTrafficOut int         `json:"public_traffic_out"`
+	StorageSize      int         `json:"storage_size"`
+	StorageTier      StorageTier `json:"storage_tier"`
 }
 
 // Plans represents all UpCloud plans
@@ -15,6 +15,16 @@ type Plans struct {
 	Plan *[]Plan `json:"plan"`
 }
 
+// List will return the plans as a slice
+// Note: A nil slice is returned when the value or plans are not set
+func (p *Plans) List() []Plan {
+	if p == nil || p.Plan == nil {
+		return nil
+	}
+
+	return *p.Plan
+}
+
 // getPlansResponse is a response wrapper to match the UpCloud API payload
 type getPlansResponse struct {
 	Plans *Plans `json:"plans"`
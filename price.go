@@ -0,0 +1,356 @@
+package upcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+const (
+	// PricePrefixPlan is the price key prefix for predefined plans
+	PricePrefixPlan = "server_plan_"
+	// PricePrefixStorage is the price key prefix for storage tiers
+	PricePrefixStorage = "storage_"
+	// PriceServerCore is the price key for a single core of a custom server
+	PriceServerCore = "server_core"
+	// PriceServerMemory is the price key for the memory of a custom server
+	PriceServerMemory = "server_memory"
+	// PriceIPv4Address is the price key for a public IPv4 address
+	PriceIPv4Address = "ipv4_address"
+	// PriceTemplateLicense is the item name used for template licenses within estimates
+	PriceTemplateLicense = "template_license"
+)
+
+// Price represents a single UpCloud price entry
+// Note: Price is the cost in cents per hour for every Amount units
+type Price struct {
+	Amount int     `json:"amount"`
+	Price  float64 `json:"price"`
+}
+
+// PriceZone represents the price table of a single UpCloud zone
+type PriceZone struct {
+	// Name of the zone
+	Name string
+	// Prices keyed by product (e.g. "server_plan_1xCPU-1GB", "storage_maxiops")
+	Prices map[string]Price
+}
+
+// Get will return the price for the provided product key
+func (p *PriceZone) Get(key string) (price Price, ok bool) {
+	price, ok = p.Prices[key]
+	return
+}
+
+// UnmarshalJSON will unmarshal a zone price table
+// Note: UpCloud returns every product as a sibling of the zone name
+func (p *PriceZone) UnmarshalJSON(bs []byte) (err error) {
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(bs, &raw); err != nil {
+		return
+	}
+
+	if name, ok := raw["name"]; ok {
+		if err = json.Unmarshal(name, &p.Name); err != nil {
+			return
+		}
+
+		delete(raw, "name")
+	}
+
+	p.Prices = make(map[string]Price, len(raw))
+	for key, value := range raw {
+		var price Price
+		if err = json.Unmarshal(value, &price); err != nil {
+			return fmt.Errorf("error parsing price \"%s\": %v", key, err)
+		}
+
+		p.Prices[key] = price
+	}
+
+	return
+}
+
+// MarshalJSON will marshal a zone price table into the UpCloud API format
+func (p PriceZone) MarshalJSON() (bs []byte, err error) {
+	var raw = make(map[string]interface{}, len(p.Prices)+1)
+	for key, price := range p.Prices {
+		raw[key] = price
+	}
+
+	raw["name"] = p.Name
+	return json.Marshal(raw)
+}
+
+// PriceZones represents the price tables of all UpCloud zones
+type PriceZones struct {
+	Zone *[]PriceZone `json:"zone"`
+}
+
+// List will return the price zones as a slice
+// Note: A nil slice is returned when the value or price zones are not set
+func (p *PriceZones) List() []PriceZone {
+	if p == nil || p.Zone == nil {
+		return nil
+	}
+
+	return *p.Zone
+}
+
+// getPricesResponse is a response wrapper to match the UpCloud API payload
+type getPricesResponse struct {
+	Prices *PriceZones `json:"prices"`
+}
+
+// CostItem represents a single line of a cost estimate
+type CostItem struct {
+	// Price key of the item (e.g. "server_plan_1xCPU-1GB")
+	Name string `json:"name"`
+	// Quantity of the item (e.g. GB of storage, MB of memory, count of addresses)
+	Quantity int `json:"quantity"`
+	// Price of the item in cents per hour
+	Hourly float64 `json:"hourly"`
+	// Price of the item in cents for the estimated duration
+	Total float64 `json:"total"`
+}
+
+// CostEstimate represents an itemized cost estimate for a server
+type CostEstimate struct {
+	Zone  string     `json:"zone"`
+	Hours int        `json:"hours"`
+	Items []CostItem `json:"items"`
+	// Hourly cost of all items in cents
+	Hourly float64 `json:"hourly"`
+	// Total cost of all items in cents for the estimated duration
+	Total float64 `json:"total"`
+}
+
+func (c *CostEstimate) add(pz *PriceZone, name string, quantity int) (err error) {
+	var (
+		price Price
+		ok    bool
+	)
+
+	if price, ok = pz.Get(name); !ok {
+		return fmt.Errorf("price \"%s\" not available in zone \"%s\"", name, pz.Name)
+	}
+
+	return c.addHourly(name, quantity, price.Price*units(quantity, price.Amount))
+}
+
+func (c *CostEstimate) addHourly(name string, quantity int, hourly float64) (err error) {
+	var item CostItem
+	item.Name = name
+	item.Quantity = quantity
+	item.Hourly = hourly
+	item.Total = hourly * float64(c.Hours)
+
+	c.Items = append(c.Items, item)
+	c.Hourly += item.Hourly
+	c.Total += item.Total
+	return
+}
+
+// EstimateServerCost will estimate the cost of running the provided server for the given amount of hours
+// Note: When zone is empty, the zone of the server details is used
+func (u *UpCloud) EstimateServerCost(serverDetails *ServerDetails, zone string, hours int) (e *CostEstimate, err error) {
+	if zone == "" {
+		zone = serverDetails.Zone
+	}
+
+	var prices *[]PriceZone
+	if prices, err = u.GetPrices(); err != nil {
+		return
+	}
+
+	var pz *PriceZone
+	for i := range *prices {
+		if (*prices)[i].Name == zone {
+			pz = &(*prices)[i]
+			break
+		}
+	}
+
+	if pz == nil {
+		err = fmt.Errorf("prices not available for zone \"%s\"", zone)
+		return
+	}
+
+	var plans *[]Plan
+	if plans, err = u.GetPlans(); err != nil {
+		return
+	}
+
+	var templates *[]Storage
+	if hasStorageAction(serverDetails, "clone") {
+		if templates, err = u.GetStorages(Template); err != nil {
+			return
+		}
+	}
+
+	return estimateServerCost(pz, plans, templates, serverDetails, hours)
+}
+
+func estimateServerCost(pz *PriceZone, plans *[]Plan, templates *[]Storage, sd *ServerDetails, hours int) (e *CostEstimate, err error) {
+	var est CostEstimate
+	est.Zone = pz.Name
+	est.Hours = hours
+
+	// Storage included within the plan is deducted from the first disk
+	var planStorage int
+	var plan *Plan
+	if sd.Plan != "" && sd.Plan != "custom" {
+		if plan = findPlan(plans, sd.Plan); plan == nil {
+			err = fmt.Errorf("plan \"%s\" not found", sd.Plan)
+			return
+		}
+
+		if err = est.add(pz, PricePrefixPlan+plan.Name, 1); err != nil {
+			return
+		}
+
+		planStorage = plan.StorageSize
+	} else {
+		if sd.CoreNumber <= 0 || sd.MemoryAmount <= 0 {
+			err = fmt.Errorf("invalid custom server of %d cores and %d MB of memory", sd.CoreNumber, sd.MemoryAmount)
+			return
+		}
+
+		if err = est.add(pz, PriceServerCore, int(sd.CoreNumber)); err != nil {
+			return
+		}
+
+		if err = est.add(pz, PriceServerMemory, int(sd.MemoryAmount)); err != nil {
+			return
+		}
+	}
+
+	if sd.StorageDevices != nil && sd.StorageDevices.StorageDevice != nil {
+		for _, device := range *sd.StorageDevices.StorageDevice {
+			if device.Action != "create" && device.Action != "clone" {
+				// Attached storages are already being billed
+				continue
+			}
+
+			var template *Storage
+			if device.Action == "clone" {
+				template = findStorage(templates, device.Storage)
+			}
+
+			var size = device.StorageSize
+			if size == 0 && template != nil {
+				size = template.Size
+			}
+
+			if template != nil && template.License > 0 {
+				if err = est.addHourly(PriceTemplateLicense, 1, template.License); err != nil {
+					return
+				}
+			}
+
+			var tier = device.Tier
+			if planStorage > 0 {
+				if tier == "" || tier == plan.StorageTier {
+					tier = plan.StorageTier
+					// Deduct the plan storage from the first disk and skip billing when it fits
+					if size -= planStorage; size < 0 {
+						size = 0
+					}
+
+					planStorage = 0
+				}
+			}
+
+			if size == 0 {
+				continue
+			}
+
+			if tier == "" {
+				tier = DefaultStorageTier
+			}
+
+			if err = est.add(pz, PricePrefixStorage+string(tier), size); err != nil {
+				return
+			}
+		}
+	}
+
+	if ipv4 := countPublicIPv4(sd); ipv4 > 0 {
+		if err = est.add(pz, PriceIPv4Address, ipv4); err != nil {
+			return
+		}
+	}
+
+	e = &est
+	return
+}
+
+func units(quantity, amount int) float64 {
+	if amount <= 0 {
+		return float64(quantity)
+	}
+
+	return math.Ceil(float64(quantity) / float64(amount))
+}
+
+func findPlan(plans *[]Plan, name string) *Plan {
+	if plans == nil {
+		return nil
+	}
+
+	for i := range *plans {
+		if (*plans)[i].Name == name {
+			return &(*plans)[i]
+		}
+	}
+
+	return nil
+}
+
+func findStorage(storages *[]Storage, uuid string) *Storage {
+	if storages == nil {
+		return nil
+	}
+
+	for i := range *storages {
+		if (*storages)[i].UUID == uuid {
+			return &(*storages)[i]
+		}
+	}
+
+	return nil
+}
+
+func hasStorageAction(sd *ServerDetails, action string) bool {
+	if sd.StorageDevices == nil || sd.StorageDevices.StorageDevice == nil {
+		return false
+	}
+
+	for _, device := range *sd.StorageDevices.StorageDevice {
+		if device.Action == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countPublicIPv4(sd *ServerDetails) (count int) {
+	if sd.Networking == nil || sd.Networking.Interfaces == nil || sd.Networking.Interfaces.Interface == nil {
+		return
+	}
+
+	for _, iface := range *sd.Networking.Interfaces.Interface {
+		if iface.Type != "public" || iface.IPAddresses == nil || iface.IPAddresses.IPAddress == nil {
+			continue
+		}
+
+		for _, ip := range *iface.IPAddresses.IPAddress {
+			if ip.Family == IPv4 {
+				count++
+			}
+		}
+	}
+
+	return
+}
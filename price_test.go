@@ -0,0 +1,128 @@
+package upcloud
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testPrices = `{
+	"prices": {
+		"zone": [
+			{
+				"name": "us-chi1",
+				"ipv4_address": {"amount": 1, "price": 0.3},
+				"server_core": {"amount": 1, "price": 1.2},
+				"server_memory": {"amount": 256, "price": 0.2},
+				"server_plan_1xCPU-2GB": {"amount": 1, "price": 1.5},
+				"storage_hdd": {"amount": 1, "price": 0.01},
+				"storage_maxiops": {"amount": 1, "price": 0.03}
+			}
+		]
+	}
+}`
+
+func testPriceZone(t *testing.T) (pz *PriceZone) {
+	var err error
+	var resp getPricesResponse
+	if err = json.Unmarshal([]byte(testPrices), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	pz = &(*resp.Prices.Zone)[0]
+	return
+}
+
+func TestPriceZone_UnmarshalJSON(t *testing.T) {
+	pz := testPriceZone(t)
+
+	if pz.Name != "us-chi1" {
+		t.Fatalf("invalid zone name, expected \"%s\" and received \"%s\"", "us-chi1", pz.Name)
+	}
+
+	var (
+		price Price
+		ok    bool
+	)
+
+	if price, ok = pz.Get(PriceServerMemory); !ok {
+		t.Fatalf("price \"%s\" not found", PriceServerMemory)
+	}
+
+	if price.Amount != 256 || price.Price != 0.2 {
+		t.Fatalf("invalid price, received %+v", price)
+	}
+}
+
+func TestEstimateServerCost(t *testing.T) {
+	var err error
+	pz := testPriceZone(t)
+
+	var plans = &[]Plan{{
+		Name:        "1xCPU-2GB",
+		CoreNumber:  1,
+		StorageSize: 50,
+		StorageTier: StorageTierMaxIOPS,
+	}}
+
+	var templates = &[]Storage{{
+		UUID:    "01000000-0000-4000-8000-000030200200",
+		License: 2,
+		Size:    10,
+	}}
+
+	var serverDetails = &ServerDetails{
+		Plan: "1xCPU-2GB",
+		Networking: &Networking{
+			Interfaces: &Interfaces{
+				Interface: &[]Interface{{
+					IPAddresses: &IPAddresses{
+						IPAddress: &[]IPAddress{{
+							Family: IPv4,
+						}}},
+					Type: "public",
+				}},
+			}},
+		StorageDevices: &StorageDevices{
+			StorageDevice: &[]StorageDevice{{
+				Action:      "clone",
+				Storage:     "01000000-0000-4000-8000-000030200200",
+				StorageSize: 60,
+			}, {
+				Action:      "create",
+				StorageSize: 100,
+			}}},
+	}
+
+	var e *CostEstimate
+	if e, err = estimateServerCost(pz, plans, templates, serverDetails, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	// Plan 1.5 + license 2 + 10GB maxiops 0.3 + 100GB of the default tier (maxiops) 3 + IPv4 0.3
+	if len(e.Items) != 5 {
+		t.Fatalf("invalid number of items, expected %d and received %d (%+v)", 5, len(e.Items), e.Items)
+	}
+
+	if !floatEquals(e.Hourly, 7.1) || !floatEquals(e.Total, 71) {
+		t.Fatalf("invalid estimate, received %f hourly and %f total", e.Hourly, e.Total)
+	}
+
+	serverDetails.Plan = ""
+	serverDetails.CoreNumber = 2
+	serverDetails.MemoryAmount = 1000
+	serverDetails.StorageDevices = nil
+
+	if e, err = estimateServerCost(pz, plans, templates, serverDetails, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cores 2.4 + memory 4 units 0.8 + IPv4 0.3
+	if !floatEquals(e.Total, 3.5) {
+		t.Fatalf("invalid estimate, received %f total", e.Total)
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	var diff = a - b
+	return diff < 1e-9 && diff > -1e-9
+}
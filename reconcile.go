@@ -0,0 +1,604 @@
+package upcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrMissingOwner is returned when pruning is enabled for a spec without an owner
+var ErrMissingOwner = errors.New("spec owner is required for pruning")
+
+var uuidExpr = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+const (
+	// ActionCreate creates a server missing from the account
+	ActionCreate ReconcileAction = "create"
+	// ActionModify modifies the title, plan or tags of a drifted server
+	// Note: Tags are assigned and removed with TagServer and UntagServer, ModifyServer leaves them unchanged
+	ActionModify ReconcileAction = "modify"
+	// ActionDelete deletes an unmanaged server tagged by the owner
+	ActionDelete ReconcileAction = "delete"
+)
+
+// ReconcileAction represents the action of a reconcile change
+type ReconcileAction string
+
+// Spec represents the desired servers of an account, e.g.
+//
+//	owner: web-stack
+//	prune: true
+//	servers:
+//	  - hostname: web-1
+//	    zone: fi-hel1
+//	    plan: 1xCPU-2GB
+//	    template: ubuntu
+//	    disks:
+//	      - size: 50
+//	        tier: maxiops
+//	    networks:
+//	      - type: public
+//	      - type: utility
+//	    tags: [web]
+//
+// Note: Servers are matched by hostname and zone. Templates, disks and networks are only applied on creation,
+// drift of disks and networks is listed within the plan but left unchanged
+type Spec struct {
+	// Tag added to every server of the spec, servers tagged by the owner but missing from the spec are unmanaged
+	Owner string `yaml:"owner"`
+	// Prune will delete unmanaged servers, along with their storages
+	Prune bool `yaml:"prune"`
+
+	Servers []ServerSpec `yaml:"servers"`
+}
+
+// ServerSpec represents a desired server
+type ServerSpec struct {
+	Hostname string `yaml:"hostname"`
+	// Note: The hostname is used when no title is set
+	Title string `yaml:"title"`
+	Zone  string `yaml:"zone"`
+	Plan  string `yaml:"plan"`
+	// Template UUID or OS family (e.g. "ubuntu") of the boot disk
+	// Note: The first disk sets the size and tier of the boot disk, the remaining disks are created empty
+	Template string        `yaml:"template"`
+	Disks    []DiskSpec    `yaml:"disks"`
+	Networks []NetworkSpec `yaml:"networks"`
+	Tags     []string      `yaml:"tags"`
+}
+
+// DiskSpec represents a desired disk
+type DiskSpec struct {
+	// Note: The hostname with a "-disk<index>" suffix is used when no title is set
+	Title string `yaml:"title"`
+	// Size in gigabytes, a boot disk of size 0 uses the size of the template or plan
+	Size int         `yaml:"size"`
+	Tier StorageTier `yaml:"tier"`
+}
+
+// NetworkSpec represents a desired network interface
+type NetworkSpec struct {
+	// Type of the interface: public, utility or private
+	Type string `yaml:"type"`
+	// Family of public interfaces, IPv4 or IPv6 (defaults to IPv4)
+	Family IPAddressFamily `yaml:"family"`
+	// Network UUID of private interfaces
+	Network string `yaml:"network"`
+}
+
+// String will return the network as "public <family>", "utility" or "private <network>"
+func (n NetworkSpec) String() string {
+	switch n.Type {
+	case "public":
+		if n.Family == "" {
+			return "public " + string(IPv4)
+		}
+
+		return "public " + string(n.Family)
+	case "private":
+		return "private " + n.Network
+	default:
+		return n.Type
+	}
+}
+
+// ReadSpec will read a spec from the provided YAML file
+func ReadSpec(filename string) (s *Spec, err error) {
+	var bs []byte
+	if bs, err = ioutil.ReadFile(filename); err != nil {
+		return
+	}
+
+	return ParseSpec(bs)
+}
+
+// ParseSpec will parse and validate a YAML spec
+func ParseSpec(bs []byte) (s *Spec, err error) {
+	var spec Spec
+	if err = yaml.Unmarshal(bs, &spec); err != nil {
+		return
+	}
+
+	if err = spec.Validate(); err != nil {
+		return
+	}
+
+	s = &spec
+	return
+}
+
+// Validate will return an error when the spec is invalid
+func (s *Spec) Validate() (err error) {
+	if s.Prune && s.Owner == "" {
+		return ErrMissingOwner
+	}
+
+	var seen = make(map[string]bool, len(s.Servers))
+	for i := range s.Servers {
+		var ss = &s.Servers[i]
+		switch {
+		case ss.Hostname == "":
+			return fmt.Errorf("server #%d: %w", i+1, ErrMissingHostname)
+		case ss.Zone == "":
+			return fmt.Errorf("server %s: %w", ss.Hostname, ErrMissingZone)
+		case ss.Template == "" && len(ss.Disks) == 0:
+			return fmt.Errorf("server %s: %w", ss.Hostname, ErrMissingStorage)
+		case seen[ss.key()]:
+			return fmt.Errorf("server %s is declared more than once within %s", ss.Hostname, ss.Zone)
+		}
+
+		seen[ss.key()] = true
+		for _, n := range ss.Networks {
+			switch {
+			case n.Type != "public" && n.Type != "utility" && n.Type != "private":
+				return fmt.Errorf("server %s: invalid network type \"%s\"", ss.Hostname, n.Type)
+			case n.Family != "" && !n.Family.Valid():
+				return fmt.Errorf("server %s: invalid network family \"%s\"", ss.Hostname, n.Family)
+			}
+		}
+	}
+
+	return
+}
+
+// title will return the title of the server, defaulting to the hostname
+func (ss *ServerSpec) title() string {
+	if ss.Title == "" {
+		return ss.Hostname
+	}
+
+	return ss.Title
+}
+
+// key will return the key servers are matched by
+func (ss *ServerSpec) key() string {
+	return serverKey(ss.Hostname, ss.Zone)
+}
+
+// tags will return the desired tags, including the owner
+func (ss *ServerSpec) tags(owner string) (tags []string) {
+	tags = append(tags, ss.Tags...)
+	if owner != "" && !hasTag(tags, owner) {
+		tags = append(tags, owner)
+	}
+
+	return
+}
+
+// ReconcilePlan represents the changes required for an account to match a spec
+type ReconcilePlan struct {
+	Changes []ReconcileChange `json:"changes"`
+}
+
+// ReconcileChange represents a single change of a reconcile plan
+type ReconcileChange struct {
+	Action   ReconcileAction `json:"action"`
+	Hostname string          `json:"hostname"`
+	Zone     string          `json:"zone"`
+	// UUID of the existing server, empty for creations
+	UUID string `json:"uuid,omitempty"`
+	// Drifted fields of modifications, formatted as "<field>: <current> -> <desired>"
+	// Note: Disks and networks are listed for the dry-run, they are not modified
+	Diff []string `json:"diff,omitempty"`
+
+	spec    *ServerSpec
+	current *ServerDetails
+	modify  *ServerDetails
+	// Tags to assign and remove, as ModifyServer does not change tags
+	tag   []string
+	untag []string
+}
+
+// Empty will return whether or not the plan has no changes
+func (p *ReconcilePlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count will return the number of changes of the provided action
+func (p *ReconcilePlan) Count(action ReconcileAction) (n int) {
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+
+	return
+}
+
+// String will return the plan as a human readable dry-run output
+func (p *ReconcilePlan) String() string {
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		var symbol string
+		switch c.Action {
+		case ActionCreate:
+			symbol = "+"
+		case ActionModify:
+			symbol = "~"
+		case ActionDelete:
+			symbol = "-"
+		}
+
+		fmt.Fprintf(&sb, "%s %s %s (%s)", symbol, c.Action, c.Hostname, c.Zone)
+		if c.UUID != "" {
+			fmt.Fprintf(&sb, " %s", c.UUID)
+		}
+
+		sb.WriteByte('\n')
+		for _, d := range c.Diff {
+			fmt.Fprintf(&sb, "    %s\n", d)
+		}
+	}
+
+	fmt.Fprintf(&sb, "Plan: %d to create, %d to modify, %d to delete\n", p.Count(ActionCreate), p.Count(ActionModify), p.Count(ActionDelete))
+	return sb.String()
+}
+
+// PlanReconcile will return the changes required for the account to match the spec without applying them
+func (u *UpCloud) PlanReconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error) {
+	if err = spec.Validate(); err != nil {
+		return
+	}
+
+	var servers *[]Server
+	if servers, err = u.GetServers(); err != nil {
+		return
+	}
+
+	var existing = make(map[string]*Server, len(*servers))
+	for i := range *servers {
+		var s = &(*servers)[i]
+		existing[serverKey(s.Hostname, s.Zone)] = s
+	}
+
+	var plan ReconcilePlan
+	var managed = make(map[string]bool, len(spec.Servers))
+	for i := range spec.Servers {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var ss = &spec.Servers[i]
+		managed[ss.key()] = true
+
+		s, ok := existing[ss.key()]
+		if !ok {
+			plan.Changes = append(plan.Changes, ReconcileChange{Action: ActionCreate, Hostname: ss.Hostname, Zone: ss.Zone, spec: ss})
+			continue
+		}
+
+		var sd *ServerDetails
+		if sd, err = u.GetServerDetails(s.UUID); err != nil {
+			return
+		}
+
+		if c, drifted := diffServer(ss, sd, spec.Owner); drifted {
+			plan.Changes = append(plan.Changes, c)
+		}
+	}
+
+	if spec.Prune {
+		var deletions []ReconcileChange
+		for _, s := range *servers {
+			if managed[serverKey(s.Hostname, s.Zone)] || !hasTag(s.Tags.List(), spec.Owner) {
+				continue
+			}
+
+			deletions = append(deletions, ReconcileChange{Action: ActionDelete, Hostname: s.Hostname, Zone: s.Zone, UUID: s.UUID})
+		}
+
+		sort.Slice(deletions, func(i, j int) bool {
+			return serverKey(deletions[i].Hostname, deletions[i].Zone) < serverKey(deletions[j].Hostname, deletions[j].Zone)
+		})
+
+		plan.Changes = append(plan.Changes, deletions...)
+	}
+
+	p = &plan
+	return
+}
+
+// Reconcile will plan and apply the changes required for the account to match the spec
+// Note: Changes are applied in order and the first error stops the reconciliation. Servers are stopped
+// to change their plan and started again afterwards, unmanaged servers are stopped and deleted with their storages
+func (u *UpCloud) Reconcile(ctx context.Context, spec *Spec) (p *ReconcilePlan, err error) {
+	if p, err = u.PlanReconcile(ctx, spec); err != nil {
+		return
+	}
+
+	for i := range p.Changes {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var c = &p.Changes[i]
+		switch c.Action {
+		case ActionCreate:
+			err = u.reconcileCreate(c, spec.Owner)
+		case ActionModify:
+			err = u.reconcileModify(ctx, c)
+		case ActionDelete:
+			err = u.reconcileDelete(ctx, c)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("error reconciling server %s (%s) for %s: %w", c.Hostname, c.Zone, c.Action, err)
+			return
+		}
+	}
+
+	return
+}
+
+func (u *UpCloud) reconcileCreate(c *ReconcileChange, owner string) (err error) {
+	var ss = c.spec
+	var b = NewServerBuilder(ss.Zone, ss.Hostname).
+		Title(ss.Title).
+		Plan(ss.Plan).
+		Tags(ss.tags(owner)...)
+
+	var disks, index = ss.Disks, 0
+	if ss.Template != "" {
+		var template string
+		if template, err = u.ResolveTemplate(ss.Template, ss.Zone); err != nil {
+			return
+		}
+
+		var boot DiskSpec
+		if len(disks) > 0 {
+			boot, disks = disks[0], disks[1:]
+		}
+
+		b.CloneTemplateWithTier(template, diskTitle(ss, boot, index), boot.Size, boot.Tier)
+		index++
+	}
+
+	for _, d := range disks {
+		b.AddDisk(diskTitle(ss, d, index), d.Size, d.Tier)
+		index++
+	}
+
+	for _, n := range ss.Networks {
+		switch {
+		case n.Type == "utility":
+			b.UtilityNetwork()
+		case n.Type == "private":
+			b.PrivateNetwork(n.Network)
+		case n.Family == IPv6:
+			b.PublicIPv6()
+		default:
+			b.PublicIPv4()
+		}
+	}
+
+	var sd *ServerDetails
+	if sd, err = b.Build(); err != nil {
+		return
+	}
+
+	var created *ServerDetails
+	if created, err = u.CreateServer(sd); err != nil {
+		return
+	}
+
+	c.UUID = created.UUID
+	return
+}
+
+func (u *UpCloud) reconcileModify(ctx context.Context, c *ReconcileChange) (err error) {
+	if len(c.tag) > 0 {
+		if _, err = u.TagServer(c.UUID, c.tag...); err != nil {
+			return
+		}
+	}
+
+	if len(c.untag) > 0 {
+		if _, err = u.UntagServer(c.UUID, c.untag...); err != nil {
+			return
+		}
+	}
+
+	if c.modify.Title == "" && c.modify.Plan == "" {
+		return
+	}
+
+	var restart = c.modify.Plan != "" && c.current.State == ServerStateStarted
+	if restart {
+		if err = u.stopAndWait(ctx, c.UUID, Soft, DefaultStopTimeout); err != nil {
+			return
+		}
+	}
+
+	if _, err = u.ModifyServer(c.UUID, c.modify); err != nil {
+		if !restart {
+			return
+		}
+
+		// Start the server again, so a failed modification does not leave it stopped
+		if _, serr := u.StartServer(c.UUID, StartServer{}); serr != nil {
+			err = fmt.Errorf("%w, starting the server again failed: %v", err, serr)
+		}
+
+		return
+	}
+
+	if restart {
+		_, err = u.StartServer(c.UUID, StartServer{})
+	}
+
+	return
+}
+
+// reconcileDelete will stop and delete the server along with its storages
+// Note: Servers carrying the protection tag are refused with ErrServerProtected
+func (u *UpCloud) reconcileDelete(ctx context.Context, c *ReconcileChange) (err error) {
+	return u.DeleteServerWithOptions(ctx, c.UUID, DeleteServer{Storages: true, Stop: true})
+}
+
+// diffServer will return the modification of the drifted fields of the server
+func diffServer(ss *ServerSpec, sd *ServerDetails, owner string) (c ReconcileChange, drifted bool) {
+	c = ReconcileChange{Action: ActionModify, Hostname: ss.Hostname, Zone: ss.Zone, UUID: sd.UUID, spec: ss, current: sd}
+
+	var modify ServerDetails
+	if title := ss.title(); title != sd.Title {
+		modify.Title = title
+		c.Diff = append(c.Diff, fmt.Sprintf("title: %s -> %s", sd.Title, title))
+	}
+
+	if ss.Plan != "" && ss.Plan != sd.Plan {
+		modify.Plan = ss.Plan
+		c.Diff = append(c.Diff, fmt.Sprintf("plan: %s -> %s", sd.Plan, ss.Plan))
+	}
+
+	var current, desired = sd.Tags.List(), ss.tags(owner)
+	if !sameTags(current, desired) {
+		c.tag, c.untag = missingTags(current, desired), missingTags(desired, current)
+		c.Diff = append(c.Diff, fmt.Sprintf("tags: [%s] -> [%s]", strings.Join(current, ", "), strings.Join(desired, ", ")))
+	}
+
+	if current, desired := describeDisks(ss, sd); current != desired {
+		c.Diff = append(c.Diff, fmt.Sprintf("disks: [%s] -> [%s] (only applied on creation)", current, desired))
+	}
+
+	if current, desired := describeNetworks(ss, sd); current != desired {
+		c.Diff = append(c.Diff, fmt.Sprintf("networks: [%s] -> [%s] (only applied on creation)", current, desired))
+	}
+
+	c.modify = &modify
+	drifted = len(c.Diff) > 0
+	return
+}
+
+// describeDisks will return the current and desired disks of the server, formatted as "<size>GB <tier>"
+// Note: Disks of size 0 take the size of the current disk, as the size of the template or plan is not known
+func describeDisks(ss *ServerSpec, sd *ServerDetails) (current, desired string) {
+	var sizes []int
+	var cs []string
+	for _, device := range sd.StorageDevices.List() {
+		if device.Type == "disk" {
+			sizes = append(sizes, device.StorageSize)
+			cs = append(cs, fmt.Sprintf("%dGB %s", device.StorageSize, device.Tier))
+		}
+	}
+
+	var disks = ss.Disks
+	if ss.Template != "" && len(disks) == 0 {
+		disks = []DiskSpec{{}}
+	}
+
+	var ds []string
+	for i, d := range disks {
+		var size, tier = d.Size, d.Tier
+		if size == 0 && i < len(sizes) {
+			size = sizes[i]
+		}
+
+		if tier == "" {
+			tier = DefaultStorageTier
+		}
+
+		ds = append(ds, fmt.Sprintf("%dGB %s", size, tier))
+	}
+
+	return strings.Join(cs, ", "), strings.Join(ds, ", ")
+}
+
+// describeNetworks will return the current and desired network interfaces of the server
+// Note: Servers without networks within the spec use the UpCloud defaults, which are not compared
+func describeNetworks(ss *ServerSpec, sd *ServerDetails) (current, desired string) {
+	if len(ss.Networks) == 0 {
+		return
+	}
+
+	var cs []string
+	for _, iface := range sd.Networking.InterfaceList() {
+		var n = NetworkSpec{Type: iface.Type, Network: iface.Network}
+		if addresses := iface.IPAddresses.List(); len(addresses) > 0 {
+			n.Family = addresses[0].Family
+		}
+
+		cs = append(cs, n.String())
+	}
+
+	var ds []string
+	for _, n := range ss.Networks {
+		ds = append(ds, n.String())
+	}
+
+	return strings.Join(cs, ", "), strings.Join(ds, ", ")
+}
+
+func diskTitle(ss *ServerSpec, d DiskSpec, index int) string {
+	if d.Title != "" {
+		return d.Title
+	}
+
+	return fmt.Sprintf("%s-disk%d", ss.Hostname, index)
+}
+
+func serverKey(hostname, zone string) string {
+	return zone + "/" + hostname
+}
+
+// hasTag will return whether or not the tags contain the provided tag
+// Note: Tags are case-insensitive
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// missingTags will return the tags of b which are missing from a
+func missingTags(a, b []string) (missing []string) {
+	for _, tag := range b {
+		if !hasTag(a, tag) {
+			missing = append(missing, tag)
+		}
+	}
+
+	return
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, tag := range a {
+		if !hasTag(b, tag) {
+			return false
+		}
+	}
+
+	return true
+}
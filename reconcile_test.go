@@ -0,0 +1,266 @@
+package upcloud_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+const testSpec = `
+owner: web-stack
+prune: true
+servers:
+  - hostname: web-1
+    zone: fi-hel1
+    plan: 1xCPU-1GB
+    template: ubuntu
+    disks:
+      - size: 30
+      - size: 10
+        tier: hdd
+    networks:
+      - type: public
+      - type: utility
+    tags: [web]
+`
+
+func TestUpCloud_Reconcile(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var spec *upcloud.Spec
+	if spec, err = upcloud.ParseSpec([]byte(testSpec)); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unmanaged server tagged by the owner, which is pruned
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("de-fra1", "legacy").
+		CloneTemplate(upcloudtest.TemplateDebian, "legacy-disk", 10).
+		Tags("web-stack").
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	var plan *upcloud.ReconcilePlan
+	if plan, err = u.PlanReconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := plan.String(); !strings.Contains(out, "Plan: 1 to create, 0 to modify, 1 to delete") {
+		t.Fatalf("invalid plan, received %s", out)
+	}
+
+	if n := len(srv.Servers()); n != 1 {
+		t.Fatalf("invalid number of servers after planning, expected %d and received %d", 1, n)
+	}
+
+	if plan, err = u.Reconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	var servers = srv.Servers()
+	if len(servers) != 1 || servers[0].Hostname != "web-1" {
+		t.Fatalf("invalid servers, expected web-1 and received %+v", servers)
+	}
+
+	var devices = servers[0].StorageDevices.List()
+	if len(devices) != 2 || devices[0].StorageSize != 30 || devices[1].Tier != upcloud.StorageTierHDD {
+		t.Fatalf("invalid storage devices, received %+v", devices)
+	}
+
+	if n := len(servers[0].Networking.InterfaceList()); n != 2 {
+		t.Fatalf("invalid number of interfaces, expected %d and received %d", 2, n)
+	}
+
+	// Drift the server, then reconcile the plan back
+	spec.Servers[0].Plan = "2xCPU-4GB"
+	spec.Servers[0].Tags = []string{"frontend"}
+	if plan, err = u.Reconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Changes) != 1 || len(plan.Changes[0].Diff) != 2 {
+		t.Fatalf("invalid plan, expected a single modification of the plan and tags and received %s", plan)
+	}
+
+	var sd *upcloud.ServerDetails
+	if sd, err = u.WaitForState(ctx, servers[0].UUID, upcloud.ServerStateStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := sd.Tags.List(); sd.Plan != "2xCPU-4GB" || len(tags) != 2 || tags[0] != "web-stack" || tags[1] != "frontend" {
+		t.Fatalf("invalid server, expected the modified plan and tags and received %s %v", sd.Plan, sd.Tags.List())
+	}
+
+	if plan, err = u.PlanReconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if !plan.Empty() {
+		t.Fatalf("invalid plan, expected no changes and received %s", plan)
+	}
+}
+
+func TestUpCloud_ReconcileProtected(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var spec *upcloud.Spec
+	if spec, err = upcloud.ParseSpec([]byte(testSpec)); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unmanaged server tagged by the owner, which would be pruned if it was not protected
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("de-fra1", "legacy").
+		CloneTemplate(upcloudtest.TemplateDebian, "legacy-disk", 10).
+		Tags("web-stack", upcloud.DefaultProtectionTag).
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var legacy *upcloud.ServerDetails
+	if legacy, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.Reconcile(context.Background(), spec); !errors.Is(err, upcloud.ErrServerProtected) {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrServerProtected, err)
+	}
+
+	var found bool
+	for _, s := range srv.Servers() {
+		found = found || s.UUID == legacy.UUID
+	}
+
+	if !found {
+		t.Fatal("expected the protected server to remain")
+	}
+
+	for _, storage := range srv.Storages() {
+		if storage.UUID == legacy.StorageDevices.List()[0].Storage {
+			return
+		}
+	}
+
+	t.Fatal("expected the storage of the protected server to remain")
+}
+
+func TestUpCloud_PlanReconcileDisksAndNetworks(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	var spec *upcloud.Spec
+	if spec, err = upcloud.ParseSpec([]byte(testSpec)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = u.Reconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	spec.Servers[0].Disks[0].Size = 40
+	spec.Servers[0].Networks = append(spec.Servers[0].Networks, upcloud.NetworkSpec{Type: "public", Family: upcloud.IPv6})
+
+	var plan *upcloud.ReconcilePlan
+	if plan, err = u.PlanReconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = []string{
+		"disks: [30GB maxiops, 10GB hdd] -> [40GB maxiops, 10GB hdd] (only applied on creation)",
+		"networks: [public IPv4, utility] -> [public IPv4, utility, public IPv6] (only applied on creation)",
+	}
+
+	if len(plan.Changes) != 1 || strings.Join(plan.Changes[0].Diff, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("invalid plan, expected the disk and network drift and received %s", plan)
+	}
+}
+
+func TestUpCloud_ReconcileModifyError(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	u.SetPollInterval(time.Millisecond)
+
+	var spec *upcloud.Spec
+	if spec, err = upcloud.ParseSpec([]byte(testSpec)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var plan *upcloud.ReconcilePlan
+	if plan, err = u.Reconcile(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	var uuid = plan.Changes[0].UUID
+	if _, err = u.WaitForState(ctx, uuid, upcloud.ServerStateStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Fail(upcloudtest.Failure{Method: "PUT", Path: "server/" + uuid, StatusCode: 500, ErrorCode: "INTERNAL_ERROR", ErrorMessage: "Internal error."})
+	spec.Servers[0].Plan = "2xCPU-4GB"
+	if _, err = u.Reconcile(ctx, spec); err == nil {
+		t.Fatal("expected error when the modification fails")
+	}
+
+	// The server is started again after the failed modification
+	var sd *upcloud.ServerDetails
+	if sd, err = u.WaitForState(ctx, uuid, upcloud.ServerStateStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	if sd.Plan != "1xCPU-1GB" {
+		t.Fatalf("invalid plan, expected %s and received %s", "1xCPU-1GB", sd.Plan)
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	var err error
+	if _, err = upcloud.ParseSpec([]byte("prune: true\nservers: []\n")); err != upcloud.ErrMissingOwner {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrMissingOwner, err)
+	}
+
+	if _, err = upcloud.ParseSpec([]byte("servers:\n  - hostname: web\n    zone: fi-hel1\n")); !errors.Is(err, upcloud.ErrMissingStorage) {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrMissingStorage, err)
+	}
+
+	if _, err = upcloud.ParseSpec([]byte("servers:\n  - zone: fi-hel1\n    template: ubuntu\n")); !errors.Is(err, upcloud.ErrMissingHostname) {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrMissingHostname, err)
+	}
+
+	if _, err = upcloud.ParseSpec([]byte("servers:\n  - hostname: web\n    template: ubuntu\n")); !errors.Is(err, upcloud.ErrMissingZone) {
+		t.Fatalf("invalid error, expected %v and received %v", upcloud.ErrMissingZone, err)
+	}
+
+	if _, err = upcloud.ParseSpec([]byte("servers:\n  - hostname: web\n    zone: fi-hel1\n    template: ubuntu\n    networks:\n      - type: vpn\n")); err == nil {
+		t.Fatal("expected error parsing an invalid network type")
+	}
+}
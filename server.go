@@ -12,57 +12,138 @@ const (
 type Tags struct {
 	Tag *[]string `json:"tag,omitempty"`
 }
+
+// List will return the tags as a slice
+// Note: A nil slice is returned when the value or tags are not set
+func (t *Tags) List() []string {
+	if t == nil || t.Tag == nil {
+		return nil
+	}
+
+	return *t.Tag
+}
+
 type IPAddress struct {
-	Access  string `json:"access,omitempty"`
-	Address string `json:"address,omitempty"`
-	Family  string `json:"family,omitempty"`
+	Access   string          `json:"access,omitempty"`
+	Address  string          `json:"address,omitempty"`
+	Family   IPAddressFamily `json:"family,omitempty"`
+	Floating *YesNo          `json:"floating,omitempty"`
 }
 type IPAddresses struct {
 	IPAddress *[]IPAddress `json:"ip_address,omitempty"`
 }
+
+// List will return the IP addresses as a slice
+// Note: A nil slice is returned when the value or IP addresses are not set
+func (i *IPAddresses) List() []IPAddress {
+	if i == nil || i.IPAddress == nil {
+		return nil
+	}
+
+	return *i.IPAddress
+}
+
 type Interface struct {
-	Index       int          `json:"index,omitempty"`
-	IPAddresses *IPAddresses `json:"ip_addresses,omitempty"`
-	Mac         string       `json:"mac,omitempty"`
-	Network     string       `json:"network,omitempty"`
-	Type        string       `json:"type,omitempty"`
-	Bootable    string       `json:"bootable,omitempty"`
+	Index             int          `json:"index,omitempty"`
+	IPAddresses       *IPAddresses `json:"ip_addresses,omitempty"`
+	Mac               string       `json:"mac,omitempty"`
+	Network           string       `json:"network,omitempty"`
+	Type              string       `json:"type,omitempty"`
+	Bootable          *YesNo       `json:"bootable,omitempty"`
+	SourceIPFiltering *YesNo       `json:"source_ip_filtering,omitempty"`
 }
 type Interfaces struct {
 	Interface *[]Interface `json:"interface,omitempty"`
 }
+
+// List will return the interfaces as a slice
+// Note: A nil slice is returned when the value or interfaces are not set
+func (i *Interfaces) List() []Interface {
+	if i == nil || i.Interface == nil {
+		return nil
+	}
+
+	return *i.Interface
+}
+
 type Networking struct {
 	Interfaces *Interfaces `json:"interfaces,omitempty"`
 }
+
+// InterfaceList will return the network interfaces as a slice
+// Note: A nil slice is returned when the networking or interfaces are not set
+func (n *Networking) InterfaceList() []Interface {
+	if n == nil {
+		return nil
+	}
+
+	return n.Interfaces.List()
+}
+
 type StorageDevice struct {
-	Action       string `json:"action,omitempty"`
-	Address      string `json:"address,omitempty"`
-	PartOfPlan   string `json:"part_of_plan,omitempty"`
-	Storage      string `json:"storage,omitempty"`
-	StorageSize  int    `json:"storage_size,omitempty"`
-	StorageTitle string `json:"storage_title,omitempty"`
-	Type         string `json:"type,omitempty"`
-	Title        string `json:"title,omitempty"`
-	BootDisk     string `json:"boot_disk,omitempty"`
+	Action       string      `json:"action,omitempty"`
+	Address      string      `json:"address,omitempty"`
+	PartOfPlan   *YesNo      `json:"part_of_plan,omitempty"`
+	Storage      string      `json:"storage,omitempty"`
+	StorageSize  int         `json:"storage_size,omitempty"`
+	StorageTitle string      `json:"storage_title,omitempty"`
+	Tier         StorageTier `json:"tier,omitempty"`
+	Type         string      `json:"type,omitempty"`
+	Title        string      `json:"title,omitempty"`
+	BootDisk     string      `json:"boot_disk,omitempty"`
 }
 type StorageDevices struct {
 	StorageDevice *[]StorageDevice `json:"storage_device,omitempty"`
 }
 
+// List will return the storage devices as a slice
+// Note: A nil slice is returned when the value or storage devices are not set
+func (s *StorageDevices) List() []StorageDevice {
+	if s == nil || s.StorageDevice == nil {
+		return nil
+	}
+
+	return *s.StorageDevice
+}
+
+// SSHKeys represents SSH public keys of a login user
+type SSHKeys struct {
+	SSHKey *[]string `json:"ssh_key,omitempty"`
+}
+
+// List will return the SSH keys as a slice
+// Note: A nil slice is returned when the value or SSH keys are not set
+func (s *SSHKeys) List() []string {
+	if s == nil || s.SSHKey == nil {
+		return nil
+	}
+
+	return *s.SSHKey
+}
+
+// LoginUser represents the user created during server creation
+type LoginUser struct {
+	// Username of the user, UpCloud defaults to "root"
+	Username string `json:"username,omitempty"`
+	// CreatePassword will be nil when not specified, UpCloud defaults to creating a password
+	CreatePassword *YesNo   `json:"create_password,omitempty"`
+	SSHKeys        *SSHKeys `json:"ssh_keys,omitempty"`
+}
+
 // Server represents UpCloud server
 type Server struct {
-	CoreNumber    string `json:"core_number,omitempty"`
-	Hostname      string `json:"hostname,omitempty"`
-	License       int    `json:"license,omitempty"`
-	MemoryAmount  string `json:"memory_amount,omitempty"`
-	Plan          string `json:"plan,omitempty"`
-	PlanIvp4Bytes string `json:"plan_ivp4_bytes,omitempty"`
-	PlanIpv6Bytes string `json:"plan_ipv6_bytes,omitempty"`
-	State         string `json:"state,omitempty"`
-	Tags          *Tags  `json:"tags,omitempty"`
-	Title         string `json:"title,omitempty"`
-	UUID          string `json:"uuid,omitempty"`
-	Zone          string `json:"zone,omitempty"`
+	CoreNumber    StringInt   `json:"core_number,omitempty"`
+	Hostname      string      `json:"hostname,omitempty"`
+	License       int         `json:"license,omitempty"`
+	MemoryAmount  StringInt   `json:"memory_amount,omitempty"`
+	Plan          string      `json:"plan,omitempty"`
+	PlanIvp4Bytes StringInt   `json:"plan_ipv4_bytes,omitempty"`
+	PlanIpv6Bytes StringInt   `json:"plan_ipv6_bytes,omitempty"`
+	State         ServerState `json:"state,omitempty"`
+	Tags          *Tags       `json:"tags,omitempty"`
+	Title         string      `json:"title,omitempty"`
+	UUID          string      `json:"uuid,omitempty"`
+	Zone          string      `json:"zone,omitempty"`
 }
 
 // Servers represents all UpCloud servers
@@ -70,34 +151,48 @@ type Servers struct {
 	Server *[]Server `json:"server,omitempty"`
 }
 
+// List will return the servers as a slice
+// Note: A nil slice is returned when the value or servers are not set
+func (s *Servers) List() []Server {
+	if s == nil || s.Server == nil {
+		return nil
+	}
+
+	return *s.Server
+}
+
 // ServerDetails represents all UpCloud detailed server objects
 type ServerDetails struct {
 	BootOrder            string          `json:"boot_order,omitempty"`
-	CoreNumber           string          `json:"core_number,omitempty"`
-	Firewall             string          `json:"firewall,omitempty"`
+	CoreNumber           StringInt       `json:"core_number,omitempty"`
+	Firewall             *OnOff          `json:"firewall,omitempty"`
 	Host                 int64           `json:"host,omitempty"`
 	Hostname             string          `json:"hostname,omitempty"`
 	IPAddresses          *IPAddresses    `json:"ip_addresses,omitempty"`
+	LoginUser            *LoginUser      `json:"login_user,omitempty"`
 	License              int             `json:"license,omitempty"`
-	MemoryAmount         string          `json:"memory_amount,omitempty"`
+	MemoryAmount         StringInt       `json:"memory_amount,omitempty"`
+	Metadata             *YesNo          `json:"metadata,omitempty"`
 	Networking           *Networking     `json:"networking,omitempty"`
 	NicModel             string          `json:"nic_model,omitempty"`
+	Password             string          `json:"password,omitempty"`
 	Plan                 string          `json:"plan,omitempty"`
-	PlanIpv4Bytes        string          `json:"plan_ipv4_bytes,omitempty"`
-	PlanIpv6Bytes        string          `json:"plan_ipv6_bytes,omitempty"`
+	PlanIpv4Bytes        StringInt       `json:"plan_ipv4_bytes,omitempty"`
+	PlanIpv6Bytes        StringInt       `json:"plan_ipv6_bytes,omitempty"`
 	SimpleBackup         string          `json:"simple_backup,omitempty"`
-	State                string          `json:"state,omitempty"`
+	State                ServerState     `json:"state,omitempty"`
 	StorageDevices       *StorageDevices `json:"storage_devices,omitempty"`
 	Tags                 *Tags           `json:"tags,omitempty"`
 	Timezone             string          `json:"timezone,omitempty"`
 	Title                string          `json:"title,omitempty"`
+	UserData             string          `json:"user_data,omitempty"`
 	UUID                 string          `json:"uuid,omitempty"`
 	VideoModel           string          `json:"video_model,omitempty"`
-	RemoteAccessEnabled  string          `json:"remote_access_enabled,omitempty"`
+	RemoteAccessEnabled  *YesNo          `json:"remote_access_enabled,omitempty"`
 	RemoteAccessType     string          `json:"remote_access_type,omitempty"`
 	RemoteAccessHost     string          `json:"remote_access_host,omitempty"`
 	RemoteAccessPassword string          `json:"remote_access_password,omitempty"`
-	RemoteAccessPort     string          `json:"remote_access_port,omitempty"`
+	RemoteAccessPort     StringInt       `json:"remote_access_port,omitempty"`
 	Zone                 string          `json:"zone,omitempty"`
 }
 
@@ -2,8 +2,8 @@ package upcloud
 
 // ServerSize represents UpCloud server size
 type ServerSize struct {
-	CoreNumber   string `json:"core_number"`
-	MemoryAmount string `json:"memory_amount"`
+	CoreNumber   StringInt `json:"core_number"`
+	MemoryAmount StringInt `json:"memory_amount"`
 }
 
 // ServerSizes represents all UpCloud server sizes
@@ -11,6 +11,16 @@ type ServerSizes struct {
 	ServerSize *[]ServerSize `json:"server_size"`
 }
 
+// List will return the server sizes as a slice
+// Note: A nil slice is returned when the value or server sizes are not set
+func (s *ServerSizes) List() []ServerSize {
+	if s == nil || s.ServerSize == nil {
+		return nil
+	}
+
+	return *s.ServerSize
+}
+
 // getServerSizesResponse is a response wrapper to match the UpCloud API payload
 type getServerSizesResponse struct {
 	ServerSizes *ServerSizes `json:"server_sizes"`
@@ -0,0 +1,108 @@
+package upcloud
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ErrInvalidSSHKey is returned when an SSH public key is malformed
+var ErrInvalidSSHKey = errors.New("invalid SSH public key")
+
+// sshKeyTypes are the SSH public key types accepted by UpCloud
+var sshKeyTypes = map[string]bool{
+	"ssh-rsa":                            true,
+	"ssh-dss":                            true,
+	"ssh-ed25519":                        true,
+	"ecdsa-sha2-nistp256":                true,
+	"ecdsa-sha2-nistp384":                true,
+	"ecdsa-sha2-nistp521":                true,
+	"sk-ssh-ed25519@openssh.com":         true,
+	"sk-ecdsa-sha2-nistp256@openssh.com": true,
+}
+
+// ValidateSSHKey will validate the format of an SSH public key (e.g. "ssh-ed25519 AAAA... user@host")
+func ValidateSSHKey(key string) (err error) {
+	var fields = strings.Fields(key)
+	if len(fields) < 2 {
+		return fmt.Errorf("%w: expected key type and key data", ErrInvalidSSHKey)
+	}
+
+	var keyType = fields[0]
+	if !sshKeyTypes[keyType] {
+		return fmt.Errorf("%w: unsupported key type \"%s\"", ErrInvalidSSHKey, keyType)
+	}
+
+	var data []byte
+	if data, err = base64.StdEncoding.DecodeString(fields[1]); err != nil {
+		return fmt.Errorf("%w: key data is not valid base64", ErrInvalidSSHKey)
+	}
+
+	// Key data begins with the length prefixed key type
+	if len(data) < 4 {
+		return fmt.Errorf("%w: key data is too short", ErrInvalidSSHKey)
+	}
+
+	var size = binary.BigEndian.Uint32(data[:4])
+	if uint32(len(data)-4) < size || string(data[4:4+size]) != keyType {
+		return fmt.Errorf("%w: key data does not match key type \"%s\"", ErrInvalidSSHKey, keyType)
+	}
+
+	return
+}
+
+// ReadAuthorizedKeys will read and validate the SSH public keys of an authorized_keys file
+// Note: Comments, empty lines and key options (e.g. "no-pty") are omitted
+func ReadAuthorizedKeys(filename string) (keys []string, err error) {
+	var bs []byte
+	if bs, err = ioutil.ReadFile(filename); err != nil {
+		return
+	}
+
+	return ParseAuthorizedKeys(bs)
+}
+
+// ParseAuthorizedKeys will parse and validate the SSH public keys of authorized_keys contents
+// Note: Comments, empty lines and key options (e.g. "no-pty") are omitted
+func ParseAuthorizedKeys(bs []byte) (keys []string, err error) {
+	var (
+		scanner = bufio.NewScanner(bytes.NewReader(bs))
+		line    int
+	)
+
+	for scanner.Scan() {
+		line++
+		var key = strings.TrimSpace(scanner.Text())
+		if key == "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		key = stripKeyOptions(key)
+		if err = ValidateSSHKey(key); err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			return
+		}
+
+		keys = append(keys, key)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// stripKeyOptions will remove the options preceding the key type of an authorized_keys entry
+func stripKeyOptions(entry string) string {
+	var fields = strings.Fields(entry)
+	for i, field := range fields {
+		if sshKeyTypes[field] {
+			return strings.Join(fields[i:], " ")
+		}
+	}
+
+	return entry
+}
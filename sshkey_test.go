@@ -0,0 +1,55 @@
+package upcloud
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	testED25519Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f sdk@test"
+	testRSAKey     = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAAwDBAQ=="
+)
+
+func TestValidateSSHKey(t *testing.T) {
+	var err error
+	if err = ValidateSSHKey(testED25519Key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ValidateSSHKey(testRSAKey); err != nil {
+		t.Fatal(err)
+	}
+
+	var invalid = []string{
+		"",
+		"ssh-ed25519",
+		"ssh-foo AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f",
+		"ssh-ed25519 not-base64!",
+		// RSA key data labeled as ed25519
+		"ssh-ed25519 AAAAB3NzaC1yc2EAAAADAQABAAAAAwDBAQ==",
+	}
+
+	for _, key := range invalid {
+		if err = ValidateSSHKey(key); !errors.Is(err, ErrInvalidSSHKey) {
+			t.Fatalf("invalid error for key \"%s\", expected %v and received %v", key, ErrInvalidSSHKey, err)
+		}
+	}
+}
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	var err error
+	var contents = "# deploy keys\n\n" + testED25519Key + "\nno-pty,command=\"uptime\" " + testRSAKey + "\n"
+
+	var keys []string
+	if keys, err = ParseAuthorizedKeys([]byte(contents)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 2 || keys[0] != testED25519Key || keys[1] != testRSAKey {
+		t.Fatalf("invalid keys, received %v", keys)
+	}
+
+	if _, err = ParseAuthorizedKeys([]byte(testED25519Key + "\nssh-rsa foo\n")); !errors.Is(err, ErrInvalidSSHKey) {
+		t.Fatalf("invalid error, expected %v and received %v", ErrInvalidSSHKey, err)
+	}
+}
@@ -1,24 +1,92 @@
 package upcloud
 
-import "time"
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/hatchify/requester"
+)
 
 type getStoragesResponse struct {
 	Storages *Storages `json:"storages"`
 }
 type Storage struct {
-	Access     string    `json:"access"`
-	License    float64   `json:"license"`
-	Size       int       `json:"size"`
-	State      string    `json:"state"`
-	Tier       string    `json:"tier,omitempty"`
-	Title      string    `json:"title"`
-	Type       string    `json:"type"`
-	UUID       string    `json:"uuid"`
-	Zone       string    `json:"zone"`
-	Created    time.Time `json:"created,omitempty"`
-	Origin     string    `json:"origin,omitempty"`
-	PartOfPlan string    `json:"part_of_plan,omitempty"`
+	Access     StorageAccess `json:"access"`
+	License    float64       `json:"license"`
+	Size       int           `json:"size"`
+	State      StorageState  `json:"state"`
+	Tier       StorageTier   `json:"tier,omitempty"`
+	Title      string        `json:"title"`
+	Type       StorageType   `json:"type"`
+	UUID       string        `json:"uuid"`
+	Zone       string        `json:"zone"`
+	Created    time.Time     `json:"created,omitempty"`
+	Origin     string        `json:"origin,omitempty"`
+	PartOfPlan *YesNo        `json:"part_of_plan,omitempty"`
 }
 type Storages struct {
 	Storage *[]Storage `json:"storage"`
 }
+
+// List will return the storages as a slice
+// Note: A nil slice is returned when the value or storages are not set
+func (s *Storages) List() []Storage {
+	if s == nil || s.Storage == nil {
+		return nil
+	}
+
+	return *s.Storage
+}
+
+// CreateStorage represents the parameters for creating storages
+type CreateStorage struct {
+	// Size in gigabytes
+	Size  int         `json:"size"`
+	Tier  StorageTier `json:"tier,omitempty"`
+	Title string      `json:"title"`
+	Zone  string      `json:"zone"`
+}
+
+type createStorageRequest struct {
+	CreateStorage CreateStorage `json:"storage"`
+}
+
+// storageWrapper is a response wrapper to match the UpCloud API payload
+type storageWrapper struct {
+	Storage *Storage `json:"storage"`
+}
+
+// CreateStorage creates a new storage
+func (u *UpCloud) CreateStorage(options CreateStorage) (s *Storage, err error) {
+	var reqJSON []byte
+	if reqJSON, err = json.Marshal(createStorageRequest{CreateStorage: options}); err != nil {
+		return
+	}
+
+	var resp storageWrapper
+	// Make request to create the storage
+	if err = u.request("CreateStorage", "POST", RouteStorage, nil, reqJSON, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	s = resp.Storage
+	return
+}
+
+// DeleteStorage deletes an already existing storage
+// Note: Storages attached to a server cannot be deleted
+func (u *UpCloud) DeleteStorage(uuid string) (err error) {
+	return u.deleteStorage(uuid, "")
+}
+
+func (u *UpCloud) deleteStorage(uuid string, backups BackupPolicy) (err error) {
+	var opts requester.Opts
+	if backups != "" {
+		opts = requester.Opts{requester.NewQuery(requester.QueryParam{Key: "backups", Val: backups.String()})}
+	}
+
+	// Make request to delete the storage
+	return u.request("DeleteStorage", "DELETE", path.Join(RouteStorage, uuid), opts, nil, nil)
+}
@@ -0,0 +1,189 @@
+package upcloud
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrTemplateNotFound is returned when no template matches a template query
+var ErrTemplateNotFound = errors.New("no template matching query")
+
+var versionExpr = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// TemplateQuery represents the criteria used to find templates
+// Note: Empty fields match every template
+type TemplateQuery struct {
+	// OS family (e.g. "ubuntu", "debian", "centos", "windows"), matched against the title
+	Family string
+	// OS version (e.g. "20.04", "10", "2019"), matched against the version within the title
+	// Note: Partial versions match newer minor versions (e.g. "6" matches "CentOS 6.10")
+	Version string
+	// Case-insensitive substring of the template title
+	Title string
+	// Zone of the template, public templates are available within every zone
+	Zone string
+}
+
+// Match will return whether or not the provided storage matches the query
+func (q *TemplateQuery) Match(s *Storage) bool {
+	var title = strings.ToLower(s.Title)
+	if q.Family != "" && !containsWord(title, strings.ToLower(q.Family)) {
+		return false
+	}
+
+	if q.Title != "" && !strings.Contains(title, strings.ToLower(q.Title)) {
+		return false
+	}
+
+	if q.Version != "" && !matchVersion(templateVersion(s.Title), q.Version) {
+		return false
+	}
+
+	if q.Zone != "" && s.Zone != "" && s.Zone != q.Zone {
+		return false
+	}
+
+	return true
+}
+
+// FindTemplate will find the latest template matching the provided query for each zone
+// Note: Public templates are available in every zone and are keyed by an empty zone
+func (u *UpCloud) FindTemplate(query TemplateQuery) (uuids map[string]string, err error) {
+	var storages *[]Storage
+	if storages, err = u.GetStorages(Template); err != nil {
+		return
+	}
+
+	return findTemplates(storages, query)
+}
+
+// ResolveTemplate will return the UUID of the latest template of the OS family (e.g. "ubuntu") within the zone
+// Note: UUIDs are returned as is, ErrTemplateNotFound is returned when no template is available within the zone
+func (u *UpCloud) ResolveTemplate(template, zone string) (uuid string, err error) {
+	if uuidExpr.MatchString(template) {
+		return template, nil
+	}
+
+	var uuids map[string]string
+	if uuids, err = u.FindTemplate(TemplateQuery{Family: template, Zone: zone}); err != nil {
+		return
+	}
+
+	return zoneTemplate(uuids, template, zone)
+}
+
+func findTemplates(storages *[]Storage, query TemplateQuery) (uuids map[string]string, err error) {
+	var latest = make(map[string]*Storage)
+	if storages != nil {
+		for i := range *storages {
+			var s = &(*storages)[i]
+			if s.Type != StorageTypeTemplate || !query.Match(s) {
+				continue
+			}
+
+			if current, ok := latest[s.Zone]; !ok || isNewerTemplate(s, current) {
+				latest[s.Zone] = s
+			}
+		}
+	}
+
+	if len(latest) == 0 {
+		err = ErrTemplateNotFound
+		return
+	}
+
+	uuids = make(map[string]string, len(latest))
+	for zone, s := range latest {
+		uuids[zone] = s.UUID
+	}
+
+	return
+}
+
+func isNewerTemplate(a, b *Storage) bool {
+	switch compareVersions(templateVersion(a.Title), templateVersion(b.Title)) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+
+	if !a.Created.Equal(b.Created) {
+		return a.Created.After(b.Created)
+	}
+
+	// UUIDs of public templates increase with every release
+	return a.UUID > b.UUID
+}
+
+func templateVersion(title string) string {
+	return versionExpr.FindString(title)
+}
+
+func matchVersion(version, query string) bool {
+	if version == query {
+		return true
+	}
+
+	return strings.HasPrefix(version, query+".")
+}
+
+func compareVersions(a, b string) int {
+	var as, bs = strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var an, bn int
+		if i < len(as) {
+			an, _ = strconv.Atoi(as[i])
+		}
+
+		if i < len(bs) {
+			bn, _ = strconv.Atoi(bs[i])
+		}
+
+		switch {
+		case an > bn:
+			return 1
+		case an < bn:
+			return -1
+		}
+	}
+
+	return 0
+}
+
+func containsWord(str, word string) bool {
+	for _, field := range strings.FieldsFunc(str, isWordSeparator) {
+		if field == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isWordSeparator(r rune) bool {
+	switch r {
+	case ' ', '/', '(', ')', '-':
+		return true
+	}
+
+	return false
+}
+
+// zoneTemplate will return the template UUID found by FindTemplate for the zone
+func zoneTemplate(uuids map[string]string, template, zone string) (uuid string, err error) {
+	var ok bool
+	if uuid, ok = uuids[zone]; ok {
+		return
+	}
+
+	// Public templates are available within every zone
+	if uuid, ok = uuids[""]; !ok {
+		err = fmt.Errorf("%w within zone %s: %s", ErrTemplateNotFound, zone, template)
+	}
+
+	return
+}
@@ -0,0 +1,21 @@
+package upcloud
+
+// Timezones represents all UpCloud timezones
+type Timezones struct {
+	Timezone *[]string `json:"timezone"`
+}
+
+// List will return the timezones as a slice
+// Note: A nil slice is returned when the value or timezones are not set
+func (t *Timezones) List() []string {
+	if t == nil || t.Timezone == nil {
+		return nil
+	}
+
+	return *t.Timezone
+}
+
+// getTimezonesResponse is a response wrapper to match the UpCloud API payload
+type getTimezonesResponse struct {
+	Timezones *Timezones `json:"timezones"`
+}
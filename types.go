@@ -0,0 +1,187 @@
+package upcloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+var null = []byte("null")
+
+// YesNo represents an UpCloud boolean which is encoded as "yes" or "no"
+type YesNo bool
+
+// NewYesNo will return a pointer to a YesNo of the provided value
+// Note: This is useful for optional request fields
+func NewYesNo(value bool) *YesNo {
+	y := YesNo(value)
+	return &y
+}
+
+// String will return the UpCloud representation of the value
+func (y YesNo) String() string {
+	if y {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// MarshalJSON will marshal the value as "yes" or "no"
+func (y YesNo) MarshalJSON() (bs []byte, err error) {
+	return json.Marshal(y.String())
+}
+
+// UnmarshalJSON will unmarshal a "yes" or "no" value
+// Note: JSON booleans are accepted as well
+func (y *YesNo) UnmarshalJSON(bs []byte) (err error) {
+	var value bool
+	if value, err = parseBool(bs, "yes", "no"); err != nil {
+		return
+	}
+
+	*y = YesNo(value)
+	return
+}
+
+// OnOff represents an UpCloud boolean which is encoded as "on" or "off"
+type OnOff bool
+
+// NewOnOff will return a pointer to an OnOff of the provided value
+// Note: This is useful for optional request fields
+func NewOnOff(value bool) *OnOff {
+	o := OnOff(value)
+	return &o
+}
+
+// String will return the UpCloud representation of the value
+func (o OnOff) String() string {
+	if o {
+		return "on"
+	}
+
+	return "off"
+}
+
+// MarshalJSON will marshal the value as "on" or "off"
+func (o OnOff) MarshalJSON() (bs []byte, err error) {
+	return json.Marshal(o.String())
+}
+
+// UnmarshalJSON will unmarshal an "on" or "off" value
+// Note: JSON booleans are accepted as well
+func (o *OnOff) UnmarshalJSON(bs []byte) (err error) {
+	var value bool
+	if value, err = parseBool(bs, "on", "off"); err != nil {
+		return
+	}
+
+	*o = OnOff(value)
+	return
+}
+
+// StringInt represents an UpCloud integer which is encoded as a string
+type StringInt int
+
+// String will return the UpCloud representation of the value
+func (s StringInt) String() string {
+	return strconv.Itoa(int(s))
+}
+
+// MarshalJSON will marshal the value as a string
+func (s StringInt) MarshalJSON() (bs []byte, err error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON will unmarshal an integer encoded as a string
+// Note: JSON numbers are accepted as well
+func (s *StringInt) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = parseNumeric(bs); err != nil || str == "" {
+		return
+	}
+
+	var value int
+	if value, err = strconv.Atoi(str); err != nil {
+		return fmt.Errorf("invalid integer value %s", bs)
+	}
+
+	*s = StringInt(value)
+	return
+}
+
+// StringFloat represents an UpCloud decimal number which is encoded as a string
+type StringFloat float64
+
+// String will return the UpCloud representation of the value
+func (s StringFloat) String() string {
+	return strconv.FormatFloat(float64(s), 'f', -1, 64)
+}
+
+// MarshalJSON will marshal the value as a string
+func (s StringFloat) MarshalJSON() (bs []byte, err error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON will unmarshal a decimal number encoded as a string
+// Note: JSON numbers are accepted as well
+func (s *StringFloat) UnmarshalJSON(bs []byte) (err error) {
+	var str string
+	if str, err = parseNumeric(bs); err != nil || str == "" {
+		return
+	}
+
+	var value float64
+	if value, err = strconv.ParseFloat(str, 64); err != nil {
+		return fmt.Errorf("invalid decimal value %s", bs)
+	}
+
+	*s = StringFloat(value)
+	return
+}
+
+func parseBool(bs []byte, truthy, falsy string) (value bool, err error) {
+	if bytes.Equal(bs, null) {
+		return
+	}
+
+	if len(bs) > 0 && bs[0] != '"' {
+		if err = json.Unmarshal(bs, &value); err != nil {
+			err = fmt.Errorf("invalid boolean value %s", bs)
+		}
+
+		return
+	}
+
+	var str string
+	if err = json.Unmarshal(bs, &str); err != nil {
+		return
+	}
+
+	switch str {
+	case truthy:
+		value = true
+	case falsy, "":
+		value = false
+	default:
+		err = fmt.Errorf("invalid boolean value %s, expected \"%s\" or \"%s\"", bs, truthy, falsy)
+	}
+
+	return
+}
+
+func parseNumeric(bs []byte) (str string, err error) {
+	if bytes.Equal(bs, null) {
+		return
+	}
+
+	if len(bs) > 0 && bs[0] != '"' {
+		// Value is a plain JSON number
+		str = string(bs)
+		return
+	}
+
+	err = json.Unmarshal(bs, &str)
+	return
+}
@@ -0,0 +1,183 @@
+package upcloud
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestTypes_RoundTrip(t *testing.T) {
+	var err error
+	var src = `{"ip_addresses":{"ip_address":[{"address":"10.0.0.1","family":"IPv4","floating":"yes"}]},"bootable":"no","source_ip_filtering":"yes"}`
+
+	var iface Interface
+	if err = json.Unmarshal([]byte(src), &iface); err != nil {
+		t.Fatal(err)
+	}
+
+	if iface.Bootable == nil || *iface.Bootable {
+		t.Fatalf("invalid bootable value, received %v", iface.Bootable)
+	}
+
+	var bs []byte
+	if bs, err = json.Marshal(iface); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bs) != src {
+		t.Fatalf("invalid round trip, expected %s and received %s", src, bs)
+	}
+}
+
+func TestTypes_PartOfPlan(t *testing.T) {
+	var err error
+	var src = `{"part_of_plan":"no","storage":"01000000-0000-4000-8000-000000000001","storage_size":25}`
+
+	var device StorageDevice
+	if err = json.Unmarshal([]byte(src), &device); err != nil {
+		t.Fatal(err)
+	}
+
+	if device.PartOfPlan == nil || *device.PartOfPlan {
+		t.Fatalf("invalid part of plan value, received %v", device.PartOfPlan)
+	}
+
+	var bs []byte
+	if bs, err = json.Marshal(device); err != nil {
+		t.Fatal(err)
+	}
+
+	// Note: "no" was dropped as the zero value before the field was a pointer
+	if string(bs) != src {
+		t.Fatalf("invalid round trip, expected %s and received %s", src, bs)
+	}
+
+	var storage Storage
+	if err = json.Unmarshal([]byte(`{"uuid":"01000000-0000-4000-8000-000000000001","part_of_plan":"yes"}`), &storage); err != nil {
+		t.Fatal(err)
+	}
+
+	if storage.PartOfPlan == nil || !*storage.PartOfPlan {
+		t.Fatalf("invalid part of plan value, received %v", storage.PartOfPlan)
+	}
+}
+
+func TestTypes_PlanIPv4Bytes(t *testing.T) {
+	var err error
+	var bs []byte
+	if bs, err = ioutil.ReadFile("testdata/new-backend.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var interactions []struct {
+		Request struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		} `json:"request"`
+		Response struct {
+			Body string `json:"body"`
+		} `json:"response"`
+	}
+
+	if err = json.Unmarshal(bs, &interactions); err != nil {
+		t.Fatal(err)
+	}
+
+	var details, list string
+	for _, i := range interactions {
+		switch {
+		case i.Request.Method != "GET":
+		case i.Request.Path == "1.3/server":
+			list = i.Response.Body
+		case strings.HasPrefix(i.Request.Path, "1.3/server/"):
+			details = i.Response.Body
+		}
+	}
+
+	if details == "" || list == "" {
+		t.Fatal("expected the recorded server responses")
+	}
+
+	// Note: The recorded servers have not used any traffic, the value is replaced to tell the fields apart
+	var replacer = strings.NewReplacer(`"plan_ipv4_bytes" : "0"`, `"plan_ipv4_bytes" : "1024"`)
+
+	var resp serverDetailsWrapper
+	if err = json.Unmarshal([]byte(replacer.Replace(details)), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ServerDetails.PlanIpv4Bytes != 1024 || resp.ServerDetails.PlanIpv6Bytes != 0 {
+		t.Fatalf("invalid server details, expected %d IPv4 bytes and received %d", 1024, resp.ServerDetails.PlanIpv4Bytes)
+	}
+
+	var servers struct {
+		Servers Servers `json:"servers"`
+	}
+
+	if err = json.Unmarshal([]byte(replacer.Replace(list)), &servers); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, server := range servers.Servers.List() {
+		if server.PlanIvp4Bytes != 1024 || server.PlanIpv6Bytes != 0 {
+			t.Fatalf("invalid server, expected %d IPv4 bytes and received %d", 1024, server.PlanIvp4Bytes)
+		}
+	}
+
+	if len(servers.Servers.List()) == 0 {
+		t.Fatal("expected the recorded servers")
+	}
+}
+
+func TestTypes_Numeric(t *testing.T) {
+	var err error
+	var sd ServerDetails
+	if err = json.Unmarshal([]byte(`{"core_number":"4","memory_amount":8192,"firewall":"on","remote_access_port":""}`), &sd); err != nil {
+		t.Fatal(err)
+	}
+
+	if sd.CoreNumber != 4 || sd.MemoryAmount != 8192 || sd.Firewall == nil || !*sd.Firewall {
+		t.Fatalf("invalid server details, received %+v", sd)
+	}
+
+	var a Account
+	if err = json.Unmarshal([]byte(`{"username":"test","credits":"9972.2324"}`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Credits != 9972.2324 {
+		t.Fatalf("invalid credits, expected %v and received %v", 9972.2324, a.Credits)
+	}
+
+	var z Zone
+	if err = json.Unmarshal([]byte(`{"id":"de-fra1","public":"maybe"}`), &z); err == nil {
+		t.Fatal("expected error for invalid yes/no value")
+	}
+}
+
+func TestTypes_Enums(t *testing.T) {
+	var err error
+	var s Storage
+	if err = json.Unmarshal([]byte(`{"access":"Public","state":"online","tier":"ssd","type":"template"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Access != StorageAccessPublic || s.Type != StorageTypeTemplate || s.State != StorageStateOnline {
+		t.Fatalf("invalid storage, received %+v", s)
+	}
+
+	// Unknown values are preserved rather than rejected
+	if s.Tier != "ssd" || s.Tier.Valid() {
+		t.Fatalf("invalid storage tier, received %v", s.Tier)
+	}
+
+	var ip IPAddress
+	if err = json.Unmarshal([]byte(`{"family":"ipv6"}`), &ip); err != nil {
+		t.Fatal(err)
+	}
+
+	if ip.Family != IPv6 {
+		t.Fatalf("invalid IP address family, expected %v and received %v", IPv6, ip.Family)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/hatchify/requester"
 )
@@ -27,6 +29,14 @@ const (
 	RouteGetServerSize = "server_size"
 	// RouteServer manages all the servers
 	RouteServer = "server"
+	// RouteGetPrice gets the prices of all the zones
+	RouteGetPrice = "price"
+	// RouteGetTimezone gets all the timezones
+	RouteGetTimezone = "timezone"
+	// RouteStorage manages all the storages
+	RouteStorage = "storage"
+	// RouteNetwork gets all the networks
+	RouteNetwork = "network"
 )
 
 // RouteGetStorageFilter gets all the storage options for the server
@@ -49,10 +59,10 @@ func New(username, password string) (up *UpCloud, err error) {
 
 	u.req = requester.New(&http.Client{}, Hostname)
 
-	// Set username
-	u.username = username
-	// Set password
-	u.password = password
+	// We authenticate with BasicAuth
+	u.Use(BasicAuth(username, password))
+	// These content-type headers are needed for when we post things
+	u.Use(Headers(requester.Header{Key: "Content-Type", Val: "application/json"}))
 	// Assign pointer reference
 	up = &u
 	return
@@ -66,36 +76,64 @@ func (u *UpCloud) SetRequester(newReq requester.Interface) {
 type UpCloud struct {
 	req requester.Interface
 
-	// Login credentials
-	username string
-	password string
+	// Request middlewares, including authentication
+	middlewares []Middleware
+	// Circuit breaker wrapping every request, nil when disabled
+	circuitBreaker *CircuitBreaker
+
+	// Interval between requests while waiting for a server state
+	pollInterval time.Duration
+
+	// Request logging
+	logger Logger
+	debug  bool
+
+	// Request instrumentation
+	tracer  Tracer
+	metrics Metrics
 }
 
-func (u *UpCloud) request(method, endpoint string, opts requester.Opts, body []byte, resp interface{}) (err error) {
+func (u *UpCloud) request(operation, method, endpoint string, opts requester.Opts, body []byte, resp interface{}) (err error) {
 	var res *http.Response
-
-	// We authenticate with BasicAuth
-	var setBasicAuth requester.Modifier = func(request *http.Request, client *http.Client) (err error) {
-		request.SetBasicAuth(u.username, u.password)
-		return nil
+	var ri = requestInfo{operation: operation, method: method, endpoint: endpoint, body: body}
+	var send RoundTrip = func(req *Request) (*http.Response, error) {
+		var opts = req.Opts
+		if u.debug {
+			// Headers are captured last so the logged headers match the sent headers
+			opts = append(opts, ri.captureHeaders())
+		}
+
+		return u.req.Request(req.Method, u.getURL(req.Endpoint), req.Body, opts)
 	}
 
-	// These content-type headers are needed for when we post things
-	var setHeaders requester.Headers = requester.NewHeaders(requester.Header{
-		Key: "Content-Type",
-		Val: "application/json",
-	})
+	var req = Request{Operation: operation, Method: method, Endpoint: endpoint, Body: body, Opts: opts}
 
-	opts = append(opts, setBasicAuth, setHeaders)
+	// Log and instrument the request once it has been processed
+	var span = u.startSpan(operation)
+	defer u.instrumentRequest(span, &ri, resp)
+	defer u.logRequest(&ri)
 
-	if res, err = u.req.Request(method, u.getURL(endpoint), body, opts); err != nil {
+	var start = time.Now()
+	if res, err = u.chain(send)(&req); err != nil {
+		ri.latency = time.Since(start)
+		ri.err = err
 		return
 	}
 	// Defer closing the HTTP response body
 	defer res.Body.Close()
 
+	ri.latency = time.Since(start)
+	ri.status = res.StatusCode
+	if u.debug {
+		if err = ri.captureResponse(res); err != nil {
+			ri.err = err
+			return
+		}
+	}
+
 	// Process HTTP response from UpCloud API
-	return u.processResponse(res, resp)
+	ri.err = u.processResponse(res, resp)
+	return ri.err
 }
 
 func (u *UpCloud) getURL(endpoint string) (url string) {
@@ -134,7 +172,7 @@ func (u *UpCloud) processError(body io.Reader) (err error) {
 func (u *UpCloud) GetAccount() (a *Account, err error) {
 	var resp getAccountResponse
 	// Make request to "Get Account" route
-	if err = u.request("GET", RouteGetAccount, nil, nil, &resp); err != nil {
+	if err = u.request("GetAccount", "GET", RouteGetAccount, nil, nil, &resp); err != nil {
 		return
 	}
 
@@ -147,12 +185,48 @@ func (u *UpCloud) GetAccount() (a *Account, err error) {
 func (u *UpCloud) GetZones() (z *[]Zone, err error) {
 	var resp getZonesResponse
 	// Make request to "Get Zones" route
-	if err = u.request("GET", RouteGetZone, nil, nil, &resp); err != nil {
+	if err = u.request("GetZones", "GET", RouteGetZone, nil, nil, &resp); err != nil {
 		return
 	}
 
 	// Set return value from response
-	z = resp.Zones.Zone
+	zones := resp.Zones.List()
+	z = &zones
+	return
+}
+
+// GetZone gets a zone by its ID
+// Note: ErrZoneNotFound is returned when the zone does not exist
+func (u *UpCloud) GetZone(id string) (z *Zone, err error) {
+	var zones *[]Zone
+	if zones, err = u.GetZones(); err != nil {
+		return
+	}
+
+	for i := range *zones {
+		if (*zones)[i].ID == id {
+			z = &(*zones)[i]
+			return
+		}
+	}
+
+	err = ErrZoneNotFound
+	return
+}
+
+// ZonesByCountry gets all the zones grouped by their country code (e.g. "de", "fi", "us")
+func (u *UpCloud) ZonesByCountry() (zc map[string][]Zone, err error) {
+	var zones *[]Zone
+	if zones, err = u.GetZones(); err != nil {
+		return
+	}
+
+	zc = make(map[string][]Zone)
+	for _, z := range *zones {
+		country := z.Country()
+		zc[country] = append(zc[country], z)
+	}
+
 	return
 }
 
@@ -160,12 +234,41 @@ func (u *UpCloud) GetZones() (z *[]Zone, err error) {
 func (u *UpCloud) GetPlans() (p *[]Plan, err error) {
 	var resp getPlansResponse
 	// Make request to "Get Plans" route
-	if err = u.request("GET", RouteGetPlan, nil, nil, &resp); err != nil {
+	if err = u.request("GetPlans", "GET", RouteGetPlan, nil, nil, &resp); err != nil {
 		return
 	}
 
 	// Set return value from response
-	p = resp.Plans.Plan
+	plans := resp.Plans.List()
+	p = &plans
+	return
+}
+
+// GetTimezones gets all the timezones available for servers
+func (u *UpCloud) GetTimezones() (t *[]string, err error) {
+	var resp getTimezonesResponse
+	// Make request to "Get Timezones" route
+	if err = u.request("GetTimezones", "GET", RouteGetTimezone, nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	timezones := resp.Timezones.List()
+	t = &timezones
+	return
+}
+
+// GetPrices gets the price tables of all the zones
+func (u *UpCloud) GetPrices() (p *[]PriceZone, err error) {
+	var resp getPricesResponse
+	// Make request to "Get Prices" route
+	if err = u.request("GetPrices", "GET", RouteGetPrice, nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	prices := resp.Prices.List()
+	p = &prices
 	return
 }
 
@@ -173,33 +276,26 @@ func (u *UpCloud) GetPlans() (p *[]Plan, err error) {
 func (u *UpCloud) GetServerSizes() (p *[]ServerSize, err error) {
 	var resp getServerSizesResponse
 	// Make request to "Get Server Sizes" route
-	if err = u.request("GET", RouteGetServerSize, nil, nil, &resp); err != nil {
+	if err = u.request("GetServerSizes", "GET", RouteGetServerSize, nil, nil, &resp); err != nil {
 		return
 	}
 
 	// Set return value from response
-	p = resp.ServerSizes.ServerSize
+	serverSizes := resp.ServerSizes.List()
+	p = &serverSizes
 	return
 }
 
 // GetServers gets all the servers
 func (u *UpCloud) GetServers() (p *[]Server, err error) {
-	var resp getServersResponse
-	// Make request to "Get Servers" route
-	if err = u.request("GET", RouteServer, nil, nil, &resp); err != nil {
-		return
-	}
-
-	// Set return value from response
-	p = resp.Servers.Server
-	return
+	return u.getServers(nil)
 }
 
 // GetServerDetails gets server details based on UUID
 func (u *UpCloud) GetServerDetails(uuid string) (p *ServerDetails, err error) {
 	var resp serverDetailsWrapper
 	// Make request to "Get Servers" route
-	if err = u.request("GET", path.Join(RouteServer, uuid), nil, nil, &resp); err != nil {
+	if err = u.request("GetServerDetails", "GET", path.Join(RouteServer, uuid), nil, nil, &resp); err != nil {
 		return
 	}
 
@@ -210,15 +306,7 @@ func (u *UpCloud) GetServerDetails(uuid string) (p *ServerDetails, err error) {
 
 // GetStorages gets all the storage options
 func (u *UpCloud) GetStorages(filter RouteGetStorageFilter) (p *[]Storage, err error) {
-	var resp getStoragesResponse
-	// Make request to "Get Servers" route
-	if err = u.request("GET", string(filter), nil, nil, &resp); err != nil {
-		return
-	}
-
-	// Set return value from response
-	p = resp.Storages.Storage
-	return
+	return u.getStorages(filter, nil)
 }
 
 // CreateServer creates a new server
@@ -235,7 +323,7 @@ func (u *UpCloud) CreateServer(serverDetails *ServerDetails) (p *ServerDetails,
 
 	var resp serverDetailsWrapper
 	//Let's go and make us a server
-	if err = u.request("POST", RouteServer, nil, reqJSON, &resp); err != nil {
+	if err = u.request("CreateServer", "POST", RouteServer, nil, reqJSON, &resp); err != nil {
 		return
 	}
 
@@ -244,6 +332,63 @@ func (u *UpCloud) CreateServer(serverDetails *ServerDetails) (p *ServerDetails,
 	return
 }
 
+// ModifyServer modifies an already existing server
+// Note: Only the set fields of the provided server details are modified
+func (u *UpCloud) ModifyServer(uuid string, serverDetails *ServerDetails) (s *ServerDetails, err error) {
+	var req = serverDetailsWrapper{
+		ServerDetails: serverDetails,
+	}
+
+	var reqJSON []byte
+	if reqJSON, err = json.Marshal(req); err != nil {
+		return
+	}
+
+	var resp serverDetailsWrapper
+	// Make request to modify the server
+	if err = u.request("ModifyServer", "PUT", path.Join(RouteServer, uuid), nil, reqJSON, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	s = resp.ServerDetails
+	return
+}
+
+// SetMetadata enables or disables the metadata service of an already existing server
+func (u *UpCloud) SetMetadata(uuid string, enabled bool) (s *ServerDetails, err error) {
+	var serverDetails ServerDetails
+	serverDetails.Metadata = NewYesNo(enabled)
+	return u.ModifyServer(uuid, &serverDetails)
+}
+
+// TagServer assigns the provided tags to an already existing server
+// Note: Tags are not changed by ModifyServer, UpCloud assigns and removes them through dedicated calls
+func (u *UpCloud) TagServer(uuid string, tags ...string) (s *ServerDetails, err error) {
+	var resp serverDetailsWrapper
+	// Make request to tag the server
+	if err = u.request("TagServer", "POST", path.Join(RouteServer, uuid, "tag", strings.Join(tags, ",")), nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	s = resp.ServerDetails
+	return
+}
+
+// UntagServer removes the provided tags from an already existing server
+func (u *UpCloud) UntagServer(uuid string, tags ...string) (s *ServerDetails, err error) {
+	var resp serverDetailsWrapper
+	// Make request to untag the server
+	if err = u.request("UntagServer", "POST", path.Join(RouteServer, uuid, "untag", strings.Join(tags, ",")), nil, nil, &resp); err != nil {
+		return
+	}
+
+	// Set return value from response
+	s = resp.ServerDetails
+	return
+}
+
 // StopServer stops an already existing server
 func (u *UpCloud) StopServer(uuid string, options StopServer) (s *ServerDetails, err error) {
 	var resp serverDetailsWrapper
@@ -258,7 +403,7 @@ func (u *UpCloud) StopServer(uuid string, options StopServer) (s *ServerDetails,
 	}
 
 	// Make request to stop the server
-	if err = u.request("POST", path.Join(RouteServer, uuid, "stop"), nil, reqJSON, &resp); err != nil {
+	if err = u.request("StopServer", "POST", path.Join(RouteServer, uuid, "stop"), nil, reqJSON, &resp); err != nil {
 		return
 	}
 
@@ -281,7 +426,7 @@ func (u *UpCloud) StartServer(uuid string, options StartServer) (s *ServerDetail
 	}
 
 	// Make request to stop the server
-	if err = u.request("POST", path.Join(RouteServer, uuid, "start"), nil, reqJSON, &resp); err != nil {
+	if err = u.request("StartServer", "POST", path.Join(RouteServer, uuid, "start"), nil, reqJSON, &resp); err != nil {
 		return
 	}
 
@@ -290,24 +435,8 @@ func (u *UpCloud) StartServer(uuid string, options StartServer) (s *ServerDetail
 	return
 }
 
-// StopServer stops an already existing server
+// DeleteServer deletes an already existing server, along with every attached storage when deleteStorage is set
+// Note: See DeleteServerWithOptions for keeping specific storages, deleting backups and protection tags
 func (u *UpCloud) DeleteServer(uuid string, deleteStorage bool) (err error) {
-	var opts requester.Opts = nil
-
-	//Parameter to delete storage associated with the server
-	if deleteStorage {
-		var queryParams = requester.NewQuery(requester.QueryParam{
-			Key: "storages",
-			Val: "1",
-		})
-
-		opts = requester.Opts{queryParams}
-	}
-
-	// Make request to stop the server
-	if err = u.request("DELETE", path.Join(RouteServer, uuid), opts, nil, nil); err != nil {
-		return
-	}
-
-	return
+	return u.deleteServer(uuid, deleteStorage, "")
 }
@@ -1,6 +1,8 @@
 package upcloud
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -133,6 +135,42 @@ func TestUpCloud_GetZones(t *testing.T) {
 	}
 }
 
+func TestUpCloud_GetZone(t *testing.T) {
+
+	var err error
+	u := setup(t)
+
+	var z *Zone
+	// Get zone by ID
+	if z, err = u.GetZone("de-fra1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !z.IsPublic() || z.Country() != "de" {
+		t.Fatalf("invalid zone, received %+v", z)
+	}
+
+	if _, err = u.GetZone("xx-nop1"); err != ErrZoneNotFound {
+		t.Fatalf("invalid error, expected %v and received %v", ErrZoneNotFound, err)
+	}
+}
+
+func TestUpCloud_ZonesByCountry(t *testing.T) {
+
+	var err error
+	u := setup(t)
+
+	var zc map[string][]Zone
+	// Get zones grouped by country
+	if zc, err = u.ZonesByCountry(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zc["fi"]) != 2 {
+		t.Fatalf("invalid number of zones in Finland, expected %d and received %d", 2, len(zc["fi"]))
+	}
+}
+
 func TestUpCloud_GetPlans(t *testing.T) {
 
 	var err error
@@ -166,7 +204,7 @@ func TestUpCloud_GetServerSizes(t *testing.T) {
 	}
 
 	for _, ss := range *serverSizes {
-		if ss.MemoryAmount == "2048" && ss.CoreNumber == "1" {
+		if ss.MemoryAmount == 2048 && ss.CoreNumber == 1 {
 			t.Log("We found our 2048mb with 1 core machine!")
 			// Output: We found our 2048mb with 1 core machine!
 		}
@@ -231,7 +269,48 @@ func TestUpCloud_GetStorages(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	t.Log((*storages)[0].Access == "public")
+	t.Log((*storages)[0].Access == StorageAccessPublic)
+}
+
+func TestUpCloud_FindTemplate(t *testing.T) {
+
+	var err error
+	u := setup(t)
+
+	var storages *[]Storage
+	// Get storages
+	if storages, err = u.GetStorages(Public); err != nil {
+		// Error encountered while getting storages
+		t.Fatal(err)
+	}
+
+	var uuids map[string]string
+	// Find the latest Ubuntu template
+	if uuids, err = findTemplates(storages, TemplateQuery{Family: "ubuntu"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if uuids[""] != "01000000-0000-4000-8000-000030200200" {
+		t.Fatalf("invalid template, expected Ubuntu 20.04 and received %s", uuids[""])
+	}
+
+	// Find the latest CentOS 6 template
+	if uuids, err = findTemplates(storages, TemplateQuery{Family: "centos", Version: "6"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if uuids[""] != "01000000-0000-4000-8000-000050010200" {
+		t.Fatalf("invalid template, expected CentOS 6.10 and received %s", uuids[""])
+	}
+
+	if _, err = findTemplates(storages, TemplateQuery{Family: "openbsd"}); err != ErrTemplateNotFound {
+		t.Fatalf("invalid error, expected %v and received %v", ErrTemplateNotFound, err)
+	}
+
+	// Only a private template within another zone
+	if _, err = zoneTemplate(map[string]string{"de-fra1": uuids[""]}, "centos", "fi-hel1"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("invalid error, expected %v and received %v", ErrTemplateNotFound, err)
+	}
 }
 
 func TestUpCloud_CreateServer(t *testing.T) {
@@ -258,7 +337,7 @@ func TestUpCloud_CreateServer(t *testing.T) {
 			Interface: &[]Interface{{
 				IPAddresses: &IPAddresses{
 					IPAddress: &[]IPAddress{{
-						Family: "IPv4",
+						Family: IPv4,
 					}}},
 				Type: "public",
 			}},
@@ -291,18 +370,77 @@ func TestUpCloud_CreateServer(t *testing.T) {
 	}
 }
 
-func TestUpCloud_CreateServerWithMocks(t *testing.T) {
-	//Implement mock based server creation very similar to regular CreateServer Test
-	//We can also implement dynamic parameters by taking requests from FileStore and dumping them into MapStore
+func TestServerBuilder_Build(t *testing.T) {
 
-	//1. Create server request gets sent and a 200 response comes back for it
-	//2. Make sure the server name matches in the response that comes back
+	var err error
+	var serverDetails *ServerDetails
+	// Build the same server as the one recorded within the mock backend
+	if serverDetails, err = NewServerBuilder("us-chi1", machineHostname).
+		CloneTemplate("01000000-0000-4000-8000-000030200200", "MadFastStripedRaid", 0).
+		PublicIPv4().
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var reqJSON []byte
+	if reqJSON, err = json.Marshal(serverDetailsWrapper{ServerDetails: serverDetails}); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `{"server":{"hostname":"sdk-test-machine","networking":{"interfaces":{"interface":[{"ip_addresses":{"ip_address":[{"family":"IPv4"}]},"type":"public"}]}},"storage_devices":{"storage_device":[{"action":"clone","storage":"01000000-0000-4000-8000-000030200200","title":"MadFastStripedRaid"}]},"title":"sdk-test-machine","zone":"us-chi1"}}`
+	if string(reqJSON) != expected {
+		t.Fatalf("invalid request, expected %s and received %s", expected, reqJSON)
+	}
+
+	if _, err = NewServerBuilder("", machineHostname).PublicIPv4().Build(); err != ErrMissingZone {
+		t.Fatalf("invalid error, expected %v and received %v", ErrMissingZone, err)
+	}
+
+	if _, err = NewServerBuilder("us-chi1", machineHostname).Build(); err != ErrMissingStorage {
+		t.Fatalf("invalid error, expected %v and received %v", ErrMissingStorage, err)
+	}
+
+	if _, err = NewServerBuilder("us-chi1", machineHostname).AddDisk("data", 0, StorageTierMaxIOPS).Build(); err == nil {
+		t.Fatal("expected error for invalid disk size")
+	}
+
+	if serverDetails, err = NewServerBuilder("us-chi1", machineHostname).
+		CloneTemplateWithTier("01000000-0000-4000-8000-000030200200", "MadFastStripedRaid", 0, StorageTierHDD).
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tier := serverDetails.StorageDevices.List()[0].Tier; tier != StorageTierHDD {
+		t.Fatalf("invalid tier, expected \"%s\" and received \"%s\"", StorageTierHDD, tier)
+	}
+
+	if _, err = NewServerBuilder("us-chi1", machineHostname).CloneTemplateWithTier("01000000-0000-4000-8000-000030200200", "", 0, "ssd").Build(); err == nil {
+		t.Fatal("expected error for invalid storage tier")
+	}
 }
 
-func TestUpCloud_FullServerCreationCycleWithMocks(t *testing.T) {
-	//Implement a mock based full working cycle for create/stop/start/stop/delete
-	//This should probably use several JsonFileStores to have different started/stopped states
-	//We should use already existing test and just doing t.Run() on them
+func TestUpCloud_CreateServerPassword(t *testing.T) {
+
+	var err error
+	u := setup(t)
+
+	var serverDetails *ServerDetails
+	if serverDetails, err = NewServerBuilder("us-chi1", machineHostname).
+		CloneTemplate("01000000-0000-4000-8000-000030200200", "MadFastStripedRaid", 0).
+		PublicIPv4().
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result *ServerDetails
+	if result, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	// The generated password is only returned when creating the server
+	if result.Password == "" {
+		t.Fatal("expected generated password within the response")
+	}
 }
 
 func ExampleUpCloud_CreateServer() {
@@ -320,30 +458,13 @@ func ExampleUpCloud_CreateServer() {
 		log.Fatal("Couldn't create UpCloud object")
 	}
 
-	var networking = &Networking{
-		Interfaces: &Interfaces{
-			Interface: &[]Interface{{
-				IPAddresses: &IPAddresses{
-					IPAddress: &[]IPAddress{{
-						Family: "IPv4",
-					}}},
-				Type: "public",
-			}},
-		}}
-
-	var storage = &StorageDevices{
-		StorageDevice: &[]StorageDevice{{
-			Action:  "clone",
-			Storage: "01000000-0000-4000-8000-000030200200",
-			Title:   "MadFastStripedRaid",
-		}}}
-
-	var serverDetails = &ServerDetails{
-		Hostname:       machineHostname,
-		Networking:     networking,
-		StorageDevices: storage,
-		Title:          machineHostname,
-		Zone:           "us-chi1",
+	var serverDetails *ServerDetails
+	// Build the details of our new server
+	if serverDetails, err = NewServerBuilder("us-chi1", machineHostname).
+		CloneTemplate("01000000-0000-4000-8000-000030200200", "MadFastStripedRaid", 0).
+		PublicIPv4().
+		Build(); err != nil {
+		log.Fatal(err)
 	}
 
 	var result *ServerDetails
@@ -376,7 +497,7 @@ func TestUpCloud_StartServer(t *testing.T) {
 			//Debug
 			t.Log(server)
 
-			if server.State == "stopped" {
+			if server.State == ServerStateStopped {
 				var serverDetails *ServerDetails
 				// Get servers details of the server we are about to stop
 				serverDetails, err = u.StartServer(server.UUID, StartServer{})
@@ -413,7 +534,7 @@ func TestUpCloud_StopServer(t *testing.T) {
 			//Debug
 			t.Log(server)
 
-			if server.State == "started" {
+			if server.State == ServerStateStarted {
 				var serverDetails *ServerDetails
 				// Get servers details of the server we are about to stop
 				serverDetails, err = u.StopServer(server.UUID, StopServer{StopType: string(Hard), Timeout: "60"})
@@ -447,7 +568,7 @@ func TestUpCloud_DeleteServer(t *testing.T) {
 
 	for _, server := range *servers {
 		if server.Hostname == machineHostname {
-			if server.State == "stopped" {
+			if server.State == ServerStateStopped {
 				if err = u.DeleteServer(server.UUID, true); err != nil {
 					t.Fatal("Unable to delete the server")
 				}
@@ -0,0 +1,242 @@
+package upcloudtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hatchify/requester"
+)
+
+// ErrInteractionNotFound is returned when replaying a request which was not recorded
+var ErrInteractionNotFound = errors.New("interaction not found within cassette")
+
+// Mode represents whether a cassette records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves recorded interactions without sending any requests
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the live API and records the interactions
+	ModeRecord
+)
+
+// record is the flag which switches cassettes to record mode (e.g. "go test -upcloud.record")
+var record = flag.Bool("upcloud.record", false, "record UpCloud API interactions into cassettes")
+
+// CurrentMode will return the mode selected by the -upcloud.record flag or the UPCLOUD_RECORD environment variable
+func CurrentMode() Mode {
+	if *record || os.Getenv("UPCLOUD_RECORD") == "1" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// Interaction represents a recorded request and its response
+// Note: The format matches the requester mock file backend
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+
+	Response struct {
+		StatusCode int    `json:"statusCode"`
+		Body       string `json:"body"`
+	} `json:"response"`
+}
+
+// NewCassette will return a requester which records to or replays from the cassette file, based on CurrentMode
+// Note: The live requester is only used when recording, Save must be called once recording is done
+func NewCassette(filename string, live requester.Interface) (c *Cassette, err error) {
+	return NewCassetteWithMode(filename, live, CurrentMode())
+}
+
+// NewCassetteWithMode will return a requester which records to or replays from the cassette file
+func NewCassetteWithMode(filename string, live requester.Interface, mode Mode) (cp *Cassette, err error) {
+	var c Cassette
+	c.filename = filename
+	c.live = live
+	c.mode = mode
+	if mode == ModeReplay {
+		var bs []byte
+		if bs, err = ioutil.ReadFile(filename); err != nil {
+			return
+		}
+
+		if err = json.Unmarshal(bs, &c.interactions); err != nil {
+			return
+		}
+
+		c.used = make([]bool, len(c.interactions))
+	}
+
+	cp = &c
+	return
+}
+
+// Cassette records and replays UpCloud API interactions
+type Cassette struct {
+	mux sync.Mutex
+
+	filename string
+	live     requester.Interface
+	mode     Mode
+
+	interactions []Interaction
+	used         []bool
+}
+
+// Mode will return the mode of the cassette
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Request will record or replay a request
+func (c *Cassette) Request(method, path string, body []byte, opts requester.Opts) (res *http.Response, err error) {
+	if path, err = withQuery(path, opts); err != nil {
+		return
+	}
+
+	if c.mode == ModeRecord {
+		return c.record(method, path, body, opts)
+	}
+
+	return c.replay(method, path, body)
+}
+
+// Save will write the sanitized interactions to the cassette file
+// Note: Save is a no-op when replaying
+func (c *Cassette) Save() (err error) {
+	if c.mode != ModeRecord {
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var bs []byte
+	if bs, err = json.MarshalIndent(c.interactions, "", "\t"); err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(c.filename, bs, 0644)
+}
+
+func (c *Cassette) record(method, path string, body []byte, opts requester.Opts) (res *http.Response, err error) {
+	// The path is recorded with its query, the requester receives the query through the options
+	if res, err = c.live.Request(method, stripQuery(path), body, opts); err != nil {
+		return
+	}
+
+	var resBody []byte
+	if resBody, err = ioutil.ReadAll(res.Body); err != nil {
+		return
+	}
+
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	var i Interaction
+	i.Request.Method = method
+	i.Request.Path = path
+	i.Request.Body = string(Sanitize(body))
+	i.Response.StatusCode = res.StatusCode
+	i.Response.Body = string(Sanitize(resBody))
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.interactions = append(c.interactions, i)
+	return
+}
+
+// replay will return the first unused interaction matching the request
+// Note: Once every matching interaction was used, the last one is repeated (e.g. when polling)
+func (c *Cassette) replay(method, path string, body []byte) (res *http.Response, err error) {
+	var normalized = normalizeBody(Sanitize(body))
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var match = -1
+	for i, interaction := range c.interactions {
+		if interaction.Request.Method != method || interaction.Request.Path != path {
+			continue
+		}
+
+		if normalizeBody([]byte(interaction.Request.Body)) != normalized {
+			continue
+		}
+
+		match = i
+		if !c.used[i] {
+			break
+		}
+	}
+
+	if match == -1 {
+		err = fmt.Errorf("%w: %s %s", ErrInteractionNotFound, method, path)
+		return
+	}
+
+	c.used[match] = true
+
+	var interaction = c.interactions[match]
+	res = &http.Response{
+		StatusCode: interaction.Response.StatusCode,
+		Status:     http.StatusText(interaction.Response.StatusCode),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+	}
+
+	return
+}
+
+// withQuery will return the path with the query set by the options (e.g. "1.3/server?limit=10")
+func withQuery(path string, opts requester.Opts) (out string, err error) {
+	var req *http.Request
+	if req, err = http.NewRequest("GET", "http://localhost/", nil); err != nil {
+		return
+	}
+
+	for _, opt := range opts {
+		if err = opt.Apply(req, &http.Client{}); err != nil {
+			return
+		}
+	}
+
+	out = path
+	if req.URL.RawQuery != "" {
+		out += "?" + req.URL.RawQuery
+	}
+
+	return
+}
+
+func stripQuery(path string) string {
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		return path[:i]
+	}
+
+	return path
+}
+
+// normalizeBody will return the JSON body with consistent key order and whitespace
+func normalizeBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(bytes.TrimSpace(body))
+	}
+
+	bs, _ := json.Marshal(value)
+	return string(bs)
+}
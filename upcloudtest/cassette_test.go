@@ -0,0 +1,107 @@
+package upcloudtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hatchify/requester"
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func TestCassette(t *testing.T) {
+	var err error
+	srv := NewServer()
+	defer srv.Close()
+
+	var dir string
+	if dir, err = ioutil.TempDir("", "cassette"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var filename = filepath.Join(dir, "create-server.json")
+	var recorder *Cassette
+	if recorder, err = NewCassetteWithMode(filename, requester.New(&http.Client{}, srv.URL), ModeRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("fi-hel1", "web").AddDisk("web-disk", 10, upcloud.StorageTierHDD).Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	u := srv.Client()
+	u.SetRequester(recorder)
+
+	var created *upcloud.ServerDetails
+	if created, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var bs []byte
+	if bs, err = ioutil.ReadFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{created.Password, created.IPAddresses.List()[0].Address, created.Networking.InterfaceList()[0].Mac} {
+		if strings.Contains(string(bs), secret) {
+			t.Fatalf("expected \"%s\" to be scrubbed from the cassette", secret)
+		}
+	}
+
+	var replayer *Cassette
+	if replayer, err = NewCassetteWithMode(filename, nil, ModeReplay); err != nil {
+		t.Fatal(err)
+	}
+
+	u.SetRequester(replayer)
+
+	var replayed *upcloud.ServerDetails
+	if replayed, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	if replayed.UUID != created.UUID || replayed.Password != Redacted {
+		t.Fatalf("invalid replayed server, received %+v", replayed)
+	}
+
+	serverDetails.Hostname = "db"
+	if _, err = u.CreateServer(serverDetails); !strings.Contains(errString(err), ErrInteractionNotFound.Error()) {
+		t.Fatalf("invalid error, expected %v and received %v", ErrInteractionNotFound, err)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	var body = Sanitize([]byte(`{"account":{"username":"hatchapi"},"address":"209.50.53.216","mac":"56:0b:73:d7:39:34","ip":"2a04:3541:1000:500:6036:dfff:fe2b:d81f"}`))
+	for _, secret := range []string{"hatchapi", "209.50.53.216", "56:0b:73:d7:39:34", "2a04:3541"} {
+		if strings.Contains(string(body), secret) {
+			t.Fatalf("expected \"%s\" to be scrubbed, received %s", secret, body)
+		}
+	}
+
+	if string(Sanitize([]byte(`{"address":"209.50.53.216"}`))) != string(Sanitize([]byte(`{"address":"209.50.53.216"}`))) {
+		t.Fatal("expected sanitizing to be deterministic")
+	}
+
+	// Dotted numbers which are not addresses are kept
+	var src = `{"oid":"1.3.6.1.4.1","title":"Debian 999.1.2.3","version":"1.2.3.4.5"}`
+	if body = Sanitize([]byte(src)); string(body) != src {
+		t.Fatalf("invalid body, expected %s and received %s", src, body)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,275 @@
+package upcloudtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hatchify/requester"
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+// DefaultFaultDelay is the delay of timeouts and slow bodies when none is set
+const DefaultFaultDelay = 30 * time.Second
+
+// FaultType represents the kind of fault to inject
+type FaultType int
+
+const (
+	// FaultTimeout waits for the delay and fails with a timeout error, without sending the request
+	FaultTimeout FaultType = iota
+	// FaultServerError responds with a 5xx error, without sending the request
+	FaultServerError
+	// FaultMalformedJSON sends the request and truncates the response body
+	FaultMalformedJSON
+	// FaultStateIllegal responds with a SERVER_STATE_ILLEGAL error, without sending the request
+	FaultStateIllegal
+	// FaultSlowBody sends the request and delays every read of the response body
+	FaultSlowBody
+)
+
+// String will return the string representation of the fault type
+func (f FaultType) String() string {
+	switch f {
+	case FaultTimeout:
+		return "timeout"
+	case FaultServerError:
+		return "server_error"
+	case FaultMalformedJSON:
+		return "malformed_json"
+	case FaultStateIllegal:
+		return "state_illegal"
+	case FaultSlowBody:
+		return "slow_body"
+	default:
+		return "unknown"
+	}
+}
+
+// Fault represents a fault injected into matching requests
+type Fault struct {
+	Type FaultType
+	// Method to match, matches every method when empty
+	Method string
+	// Path prefix to match, relative to the API version (e.g. "server" matches "server/<uuid>/stop")
+	Path string
+	// Probability of injecting the fault into a matching request, between 0 and 1
+	// Note: Zero is treated as 1 so faults are always injected by default, use Remove or Clear to turn faults off
+	Probability float64
+	// Delay of timeouts and of every read of slow bodies, defaults to DefaultFaultDelay
+	Delay time.Duration
+	// StatusCode of server errors, defaults to 503. The error code is derived from the status (e.g. BAD_GATEWAY)
+	StatusCode int
+}
+
+func (f *Fault) match(method, path string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+
+	return strings.HasPrefix(path, f.Path)
+}
+
+// NewFaultInjector will return a requester which injects faults into requests sent with the provided requester
+// Note: The seed makes the sequence of injected faults reproducible
+func NewFaultInjector(req requester.Interface, seed int64, faults ...Fault) *FaultInjector {
+	var f FaultInjector
+	f.req = req
+	f.rand = rand.New(rand.NewSource(seed))
+	f.counts = make(map[FaultType]int)
+	for _, fault := range faults {
+		f.Add(fault)
+	}
+
+	return &f
+}
+
+// FaultInjector is a requester which injects faults for chaos testing, use it with SetRequester
+type FaultInjector struct {
+	mux sync.Mutex
+
+	req    requester.Interface
+	rand   *rand.Rand
+	faults []Fault
+	counts map[FaultType]int
+}
+
+// Add will add a fault, faults are evaluated in the order they were added
+func (f *FaultInjector) Add(fault Fault) {
+	if fault.Probability <= 0 {
+		fault.Probability = 1
+	}
+
+	if fault.Delay <= 0 {
+		fault.Delay = DefaultFaultDelay
+	}
+
+	if fault.StatusCode == 0 {
+		fault.StatusCode = http.StatusServiceUnavailable
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.faults = append(f.faults, fault)
+}
+
+// Remove will remove the faults of the type, turning them off
+func (f *FaultInjector) Remove(faultType FaultType) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	// A new slice, as requests in flight may still use the rolled faults
+	var faults []Fault
+	for _, fault := range f.faults {
+		if fault.Type != faultType {
+			faults = append(faults, fault)
+		}
+	}
+
+	f.faults = faults
+}
+
+// Clear will remove every fault, requests are sent unmodified afterwards
+func (f *FaultInjector) Clear() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.faults = nil
+}
+
+// Injected will return the number of injected faults of the type
+func (f *FaultInjector) Injected(faultType FaultType) int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.counts[faultType]
+}
+
+// Request will send the request, injecting the first matching fault which is rolled
+func (f *FaultInjector) Request(method, path string, body []byte, opts requester.Opts) (res *http.Response, err error) {
+	var fault = f.roll(method, strings.TrimPrefix(path, upcloud.APIVersion+"/"))
+	if fault == nil {
+		return f.req.Request(method, path, body, opts)
+	}
+
+	switch fault.Type {
+	case FaultTimeout:
+		return nil, wait(requestContext(opts), fault.Delay)
+	case FaultServerError:
+		return errorResponse(fault.StatusCode, statusErrorCode(fault.StatusCode), "Injected server error."), nil
+	case FaultStateIllegal:
+		return errorResponse(http.StatusConflict, "SERVER_STATE_ILLEGAL", "Injected illegal server state."), nil
+	}
+
+	if res, err = f.req.Request(method, path, body, opts); err != nil {
+		return
+	}
+
+	switch fault.Type {
+	case FaultMalformedJSON:
+		var bs []byte
+		if bs, err = ioutil.ReadAll(res.Body); err != nil {
+			return
+		}
+
+		res.Body.Close()
+		// Half of the body, followed by a character which is never valid at that position
+		res.Body = ioutil.NopCloser(bytes.NewReader(append(bs[:len(bs)/2], '}')))
+	case FaultSlowBody:
+		res.Body = &slowBody{ReadCloser: res.Body, ctx: requestContext(opts), delay: fault.Delay}
+	}
+
+	return
+}
+
+// roll will return the first matching fault which is rolled, if any
+func (f *FaultInjector) roll(method, path string) *Fault {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for i := range f.faults {
+		var fault = &f.faults[i]
+		if !fault.match(method, path) || f.rand.Float64() >= fault.Probability {
+			continue
+		}
+
+		f.counts[fault.Type]++
+		return fault
+	}
+
+	return nil
+}
+
+// timeoutError is returned by injected timeouts, it matches net.Error
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "injected fault: request timed out" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// wait will wait for the delay and return a timeout error, or the error of the context once done
+func wait(ctx context.Context, delay time.Duration) error {
+	var timer = time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return timeoutError{}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestContext will return the context set by the request options
+func requestContext(opts requester.Opts) context.Context {
+	var req, _ = http.NewRequest("GET", "http://localhost/", nil)
+	for _, opt := range opts {
+		opt.Apply(req, &http.Client{})
+	}
+
+	return req.Context()
+}
+
+func errorResponse(statusCode int, code, message string) *http.Response {
+	var bs, _ = json.Marshal(map[string]interface{}{
+		"error": upcloud.Error{Code: code, Message: message},
+	})
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(bs)),
+	}
+}
+
+// statusErrorCode will return the error code of a status (e.g. BAD_GATEWAY for 502)
+func statusErrorCode(statusCode int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+}
+
+// slowBody delays every read of the underlying body
+type slowBody struct {
+	io.ReadCloser
+	ctx   context.Context
+	delay time.Duration
+}
+
+func (s *slowBody) Read(p []byte) (n int, err error) {
+	var timer = time.NewTimer(s.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-s.ctx.Done():
+		return 0, s.ctx.Err()
+	}
+
+	// Reads are limited to a few bytes so the delay applies throughout the body
+	if len(p) > 64 {
+		p = p[:64]
+	}
+
+	return s.ReadCloser.Read(p)
+}
@@ -0,0 +1,103 @@
+package upcloudtest
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hatchify/requester"
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func TestFaultInjector(t *testing.T) {
+	var err error
+	srv := NewServer()
+	defer srv.Close()
+
+	var f = NewFaultInjector(requester.New(srv.Server.Client(), srv.URL), 1,
+		Fault{Type: FaultStateIllegal, Method: "POST", Path: "server"},
+		Fault{Type: FaultMalformedJSON, Path: upcloud.RouteGetAccount},
+		Fault{Type: FaultTimeout, Path: upcloud.RouteGetZone, Delay: time.Millisecond},
+	)
+
+	u := srv.Client()
+	u.SetRequester(f)
+
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("fi-hel1", "web").AddDisk("web-disk", 10, upcloud.StorageTierHDD).Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = u.CreateServer(serverDetails)
+	if e, ok := err.(*upcloud.Error); !ok || e.Code != "SERVER_STATE_ILLEGAL" {
+		t.Fatalf("invalid error, expected SERVER_STATE_ILLEGAL and received %v", err)
+	}
+
+	if _, err = u.GetAccount(); err == nil {
+		t.Fatal("expected error decoding malformed JSON")
+	}
+
+	_, err = u.GetZones()
+	if e, ok := err.(net.Error); !ok || !e.Timeout() {
+		t.Fatalf("invalid error, expected timeout and received %v", err)
+	}
+
+	if _, err = u.GetPlans(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := f.Injected(FaultStateIllegal); n != 1 {
+		t.Fatalf("invalid number of injected faults, expected %d and received %d", 1, n)
+	}
+
+	f.Remove(FaultStateIllegal)
+	if _, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Clear()
+	if _, err = u.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaultInjector_Probability(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	inject := func() (injected []bool) {
+		var f = NewFaultInjector(requester.New(srv.Server.Client(), srv.URL), 42,
+			Fault{Type: FaultServerError, Probability: 0.5, StatusCode: http.StatusBadGateway},
+		)
+
+		u := srv.Client()
+		u.SetRequester(f)
+		for i := 0; i < 20; i++ {
+			_, err := u.GetAccount()
+			if e, ok := err.(*upcloud.Error); err != nil && (!ok || e.Code != "BAD_GATEWAY") {
+				t.Fatalf("invalid error, expected BAD_GATEWAY and received %v", err)
+			}
+
+			injected = append(injected, err != nil)
+		}
+
+		return
+	}
+
+	var first, second = inject(), inject()
+	var count int
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatal("expected the same seed to inject the same faults")
+		}
+
+		if first[i] {
+			count++
+		}
+	}
+
+	if count == 0 || count == len(first) {
+		t.Fatalf("invalid number of injected faults, received %d out of %d", count, len(first))
+	}
+}
@@ -0,0 +1,42 @@
+package upcloudtest
+
+//go:generate go run ../internal/mockgen -source ../client.go -out mock_client.go
+
+// Call represents a single call of a mock method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+func (m *MockClient) record(method string, args ...interface{}) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// Calls will return every recorded call in order
+func (m *MockClient) Calls() []Call {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return append([]Call{}, m.calls...)
+}
+
+// CallsTo will return the recorded calls of the method in order
+func (m *MockClient) CallsTo(method string) (calls []Call) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return
+}
+
+// Reset will clear the recorded calls
+func (m *MockClient) Reset() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.calls = nil
+}
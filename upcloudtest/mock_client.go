@@ -0,0 +1,539 @@
+// Code generated by mockgen from ../client.go. DO NOT EDIT.
+
+package upcloudtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hatchify/requester"
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+var _ upcloud.Client = &MockClient{}
+
+// MockClient is a mock implementation of upcloud.Client which records every call
+// Note: Methods return zero values unless their func is set
+type MockClient struct {
+	mux   sync.Mutex
+	calls []Call
+
+	SetRequesterFunc            func(newReq requester.Interface)
+	SetLoggerFunc               func(logger upcloud.Logger)
+	SetDebugFunc                func(debug bool)
+	SetTracerFunc               func(tracer upcloud.Tracer)
+	SetMetricsFunc              func(metrics upcloud.Metrics)
+	SetCircuitBreakerFunc       func(c *upcloud.CircuitBreaker)
+	UseFunc                     func(middlewares ...upcloud.Middleware)
+	SetPollIntervalFunc         func(interval time.Duration)
+	GetAccountFunc              func() (r0 *upcloud.Account, r1 error)
+	GetZonesFunc                func() (r0 *[]upcloud.Zone, r1 error)
+	GetZoneFunc                 func(id string) (r0 *upcloud.Zone, r1 error)
+	ZonesByCountryFunc          func() (r0 map[string][]upcloud.Zone, r1 error)
+	GetPlansFunc                func() (r0 *[]upcloud.Plan, r1 error)
+	GetTimezonesFunc            func() (r0 *[]string, r1 error)
+	GetPricesFunc               func() (r0 *[]upcloud.PriceZone, r1 error)
+	GetServerSizesFunc          func() (r0 *[]upcloud.ServerSize, r1 error)
+	EstimateServerCostFunc      func(serverDetails *upcloud.ServerDetails, zone string, hours int) (r0 *upcloud.CostEstimate, r1 error)
+	FindTemplateFunc            func(query upcloud.TemplateQuery) (r0 map[string]string, r1 error)
+	ResolveTemplateFunc         func(template string, zone string) (r0 string, r1 error)
+	GetServersFunc              func() (r0 *[]upcloud.Server, r1 error)
+	GetServersWithOptionsFunc   func(options upcloud.ListOptions) (r0 *[]upcloud.Server, r1 error)
+	NewServerIteratorFunc       func(options upcloud.ListOptions) (r0 *upcloud.ServerIterator)
+	GetServerDetailsFunc        func(uuid string) (r0 *upcloud.ServerDetails, r1 error)
+	GetStoragesFunc             func(filter upcloud.RouteGetStorageFilter) (r0 *[]upcloud.Storage, r1 error)
+	GetStoragesWithOptionsFunc  func(filter upcloud.RouteGetStorageFilter, options upcloud.ListOptions) (r0 *[]upcloud.Storage, r1 error)
+	NewStorageIteratorFunc      func(filter upcloud.RouteGetStorageFilter, options upcloud.ListOptions) (r0 *upcloud.StorageIterator)
+	GetFirewallRulesFunc        func(uuid string) (r0 *[]upcloud.FirewallRule, r1 error)
+	GetNetworksFunc             func() (r0 *[]upcloud.Network, r1 error)
+	ExportAccountStateFunc      func(ctx context.Context) (r0 *upcloud.AccountState, r1 error)
+	CreateServerFunc            func(serverDetails *upcloud.ServerDetails) (r0 *upcloud.ServerDetails, r1 error)
+	ModifyServerFunc            func(uuid string, serverDetails *upcloud.ServerDetails) (r0 *upcloud.ServerDetails, r1 error)
+	SetMetadataFunc             func(uuid string, enabled bool) (r0 *upcloud.ServerDetails, r1 error)
+	TagServerFunc               func(uuid string, tags ...string) (r0 *upcloud.ServerDetails, r1 error)
+	UntagServerFunc             func(uuid string, tags ...string) (r0 *upcloud.ServerDetails, r1 error)
+	StopServerFunc              func(uuid string, options upcloud.StopServer) (r0 *upcloud.ServerDetails, r1 error)
+	StartServerFunc             func(uuid string, options upcloud.StartServer) (r0 *upcloud.ServerDetails, r1 error)
+	DeleteServerFunc            func(uuid string, deleteStorage bool) (r0 error)
+	DeleteServerWithOptionsFunc func(ctx context.Context, uuid string, options upcloud.DeleteServer) (r0 error)
+	WaitForStateFunc            func(ctx context.Context, uuid string, state upcloud.ServerState) (r0 *upcloud.ServerDetails, r1 error)
+	ForEachServerFunc           func(ctx context.Context, filter upcloud.ServerFilter, fn upcloud.ServerFunc, concurrency int) (r0 []upcloud.BulkResult, r1 error)
+	BulkStopFunc                func(ctx context.Context, uuids []string, stop upcloud.StopServer, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error)
+	BulkStartFunc               func(ctx context.Context, uuids []string, start upcloud.StartServer, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error)
+	BulkDeleteFunc              func(ctx context.Context, uuids []string, deleteStorage bool, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error)
+	CreateStorageFunc           func(options upcloud.CreateStorage) (r0 *upcloud.Storage, r1 error)
+	DeleteStorageFunc           func(uuid string) (r0 error)
+	PlanReconcileFunc           func(ctx context.Context, spec *upcloud.Spec) (r0 *upcloud.ReconcilePlan, r1 error)
+	ReconcileFunc               func(ctx context.Context, spec *upcloud.Spec) (r0 *upcloud.ReconcilePlan, r1 error)
+}
+
+// SetRequester will record the call and call SetRequesterFunc
+func (m *MockClient) SetRequester(newReq requester.Interface) {
+	m.record("SetRequester", newReq)
+	if m.SetRequesterFunc == nil {
+		return
+	}
+
+	m.SetRequesterFunc(newReq)
+}
+
+// SetLogger will record the call and call SetLoggerFunc
+func (m *MockClient) SetLogger(logger upcloud.Logger) {
+	m.record("SetLogger", logger)
+	if m.SetLoggerFunc == nil {
+		return
+	}
+
+	m.SetLoggerFunc(logger)
+}
+
+// SetDebug will record the call and call SetDebugFunc
+func (m *MockClient) SetDebug(debug bool) {
+	m.record("SetDebug", debug)
+	if m.SetDebugFunc == nil {
+		return
+	}
+
+	m.SetDebugFunc(debug)
+}
+
+// SetTracer will record the call and call SetTracerFunc
+func (m *MockClient) SetTracer(tracer upcloud.Tracer) {
+	m.record("SetTracer", tracer)
+	if m.SetTracerFunc == nil {
+		return
+	}
+
+	m.SetTracerFunc(tracer)
+}
+
+// SetMetrics will record the call and call SetMetricsFunc
+func (m *MockClient) SetMetrics(metrics upcloud.Metrics) {
+	m.record("SetMetrics", metrics)
+	if m.SetMetricsFunc == nil {
+		return
+	}
+
+	m.SetMetricsFunc(metrics)
+}
+
+// SetCircuitBreaker will record the call and call SetCircuitBreakerFunc
+func (m *MockClient) SetCircuitBreaker(c *upcloud.CircuitBreaker) {
+	m.record("SetCircuitBreaker", c)
+	if m.SetCircuitBreakerFunc == nil {
+		return
+	}
+
+	m.SetCircuitBreakerFunc(c)
+}
+
+// Use will record the call and call UseFunc
+func (m *MockClient) Use(middlewares ...upcloud.Middleware) {
+	m.record("Use", middlewares)
+	if m.UseFunc == nil {
+		return
+	}
+
+	m.UseFunc(middlewares...)
+}
+
+// SetPollInterval will record the call and call SetPollIntervalFunc
+func (m *MockClient) SetPollInterval(interval time.Duration) {
+	m.record("SetPollInterval", interval)
+	if m.SetPollIntervalFunc == nil {
+		return
+	}
+
+	m.SetPollIntervalFunc(interval)
+}
+
+// GetAccount will record the call and return the result of GetAccountFunc
+func (m *MockClient) GetAccount() (r0 *upcloud.Account, r1 error) {
+	m.record("GetAccount")
+	if m.GetAccountFunc == nil {
+		return
+	}
+
+	return m.GetAccountFunc()
+}
+
+// GetZones will record the call and return the result of GetZonesFunc
+func (m *MockClient) GetZones() (r0 *[]upcloud.Zone, r1 error) {
+	m.record("GetZones")
+	if m.GetZonesFunc == nil {
+		return
+	}
+
+	return m.GetZonesFunc()
+}
+
+// GetZone will record the call and return the result of GetZoneFunc
+func (m *MockClient) GetZone(id string) (r0 *upcloud.Zone, r1 error) {
+	m.record("GetZone", id)
+	if m.GetZoneFunc == nil {
+		return
+	}
+
+	return m.GetZoneFunc(id)
+}
+
+// ZonesByCountry will record the call and return the result of ZonesByCountryFunc
+func (m *MockClient) ZonesByCountry() (r0 map[string][]upcloud.Zone, r1 error) {
+	m.record("ZonesByCountry")
+	if m.ZonesByCountryFunc == nil {
+		return
+	}
+
+	return m.ZonesByCountryFunc()
+}
+
+// GetPlans will record the call and return the result of GetPlansFunc
+func (m *MockClient) GetPlans() (r0 *[]upcloud.Plan, r1 error) {
+	m.record("GetPlans")
+	if m.GetPlansFunc == nil {
+		return
+	}
+
+	return m.GetPlansFunc()
+}
+
+// GetTimezones will record the call and return the result of GetTimezonesFunc
+func (m *MockClient) GetTimezones() (r0 *[]string, r1 error) {
+	m.record("GetTimezones")
+	if m.GetTimezonesFunc == nil {
+		return
+	}
+
+	return m.GetTimezonesFunc()
+}
+
+// GetPrices will record the call and return the result of GetPricesFunc
+func (m *MockClient) GetPrices() (r0 *[]upcloud.PriceZone, r1 error) {
+	m.record("GetPrices")
+	if m.GetPricesFunc == nil {
+		return
+	}
+
+	return m.GetPricesFunc()
+}
+
+// GetServerSizes will record the call and return the result of GetServerSizesFunc
+func (m *MockClient) GetServerSizes() (r0 *[]upcloud.ServerSize, r1 error) {
+	m.record("GetServerSizes")
+	if m.GetServerSizesFunc == nil {
+		return
+	}
+
+	return m.GetServerSizesFunc()
+}
+
+// EstimateServerCost will record the call and return the result of EstimateServerCostFunc
+func (m *MockClient) EstimateServerCost(serverDetails *upcloud.ServerDetails, zone string, hours int) (r0 *upcloud.CostEstimate, r1 error) {
+	m.record("EstimateServerCost", serverDetails, zone, hours)
+	if m.EstimateServerCostFunc == nil {
+		return
+	}
+
+	return m.EstimateServerCostFunc(serverDetails, zone, hours)
+}
+
+// FindTemplate will record the call and return the result of FindTemplateFunc
+func (m *MockClient) FindTemplate(query upcloud.TemplateQuery) (r0 map[string]string, r1 error) {
+	m.record("FindTemplate", query)
+	if m.FindTemplateFunc == nil {
+		return
+	}
+
+	return m.FindTemplateFunc(query)
+}
+
+// ResolveTemplate will record the call and return the result of ResolveTemplateFunc
+func (m *MockClient) ResolveTemplate(template string, zone string) (r0 string, r1 error) {
+	m.record("ResolveTemplate", template, zone)
+	if m.ResolveTemplateFunc == nil {
+		return
+	}
+
+	return m.ResolveTemplateFunc(template, zone)
+}
+
+// GetServers will record the call and return the result of GetServersFunc
+func (m *MockClient) GetServers() (r0 *[]upcloud.Server, r1 error) {
+	m.record("GetServers")
+	if m.GetServersFunc == nil {
+		return
+	}
+
+	return m.GetServersFunc()
+}
+
+// GetServersWithOptions will record the call and return the result of GetServersWithOptionsFunc
+func (m *MockClient) GetServersWithOptions(options upcloud.ListOptions) (r0 *[]upcloud.Server, r1 error) {
+	m.record("GetServersWithOptions", options)
+	if m.GetServersWithOptionsFunc == nil {
+		return
+	}
+
+	return m.GetServersWithOptionsFunc(options)
+}
+
+// NewServerIterator will record the call and return the result of NewServerIteratorFunc
+func (m *MockClient) NewServerIterator(options upcloud.ListOptions) (r0 *upcloud.ServerIterator) {
+	m.record("NewServerIterator", options)
+	if m.NewServerIteratorFunc == nil {
+		return
+	}
+
+	return m.NewServerIteratorFunc(options)
+}
+
+// GetServerDetails will record the call and return the result of GetServerDetailsFunc
+func (m *MockClient) GetServerDetails(uuid string) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("GetServerDetails", uuid)
+	if m.GetServerDetailsFunc == nil {
+		return
+	}
+
+	return m.GetServerDetailsFunc(uuid)
+}
+
+// GetStorages will record the call and return the result of GetStoragesFunc
+func (m *MockClient) GetStorages(filter upcloud.RouteGetStorageFilter) (r0 *[]upcloud.Storage, r1 error) {
+	m.record("GetStorages", filter)
+	if m.GetStoragesFunc == nil {
+		return
+	}
+
+	return m.GetStoragesFunc(filter)
+}
+
+// GetStoragesWithOptions will record the call and return the result of GetStoragesWithOptionsFunc
+func (m *MockClient) GetStoragesWithOptions(filter upcloud.RouteGetStorageFilter, options upcloud.ListOptions) (r0 *[]upcloud.Storage, r1 error) {
+	m.record("GetStoragesWithOptions", filter, options)
+	if m.GetStoragesWithOptionsFunc == nil {
+		return
+	}
+
+	return m.GetStoragesWithOptionsFunc(filter, options)
+}
+
+// NewStorageIterator will record the call and return the result of NewStorageIteratorFunc
+func (m *MockClient) NewStorageIterator(filter upcloud.RouteGetStorageFilter, options upcloud.ListOptions) (r0 *upcloud.StorageIterator) {
+	m.record("NewStorageIterator", filter, options)
+	if m.NewStorageIteratorFunc == nil {
+		return
+	}
+
+	return m.NewStorageIteratorFunc(filter, options)
+}
+
+// GetFirewallRules will record the call and return the result of GetFirewallRulesFunc
+func (m *MockClient) GetFirewallRules(uuid string) (r0 *[]upcloud.FirewallRule, r1 error) {
+	m.record("GetFirewallRules", uuid)
+	if m.GetFirewallRulesFunc == nil {
+		return
+	}
+
+	return m.GetFirewallRulesFunc(uuid)
+}
+
+// GetNetworks will record the call and return the result of GetNetworksFunc
+func (m *MockClient) GetNetworks() (r0 *[]upcloud.Network, r1 error) {
+	m.record("GetNetworks")
+	if m.GetNetworksFunc == nil {
+		return
+	}
+
+	return m.GetNetworksFunc()
+}
+
+// ExportAccountState will record the call and return the result of ExportAccountStateFunc
+func (m *MockClient) ExportAccountState(ctx context.Context) (r0 *upcloud.AccountState, r1 error) {
+	m.record("ExportAccountState", ctx)
+	if m.ExportAccountStateFunc == nil {
+		return
+	}
+
+	return m.ExportAccountStateFunc(ctx)
+}
+
+// CreateServer will record the call and return the result of CreateServerFunc
+func (m *MockClient) CreateServer(serverDetails *upcloud.ServerDetails) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("CreateServer", serverDetails)
+	if m.CreateServerFunc == nil {
+		return
+	}
+
+	return m.CreateServerFunc(serverDetails)
+}
+
+// ModifyServer will record the call and return the result of ModifyServerFunc
+func (m *MockClient) ModifyServer(uuid string, serverDetails *upcloud.ServerDetails) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("ModifyServer", uuid, serverDetails)
+	if m.ModifyServerFunc == nil {
+		return
+	}
+
+	return m.ModifyServerFunc(uuid, serverDetails)
+}
+
+// SetMetadata will record the call and return the result of SetMetadataFunc
+func (m *MockClient) SetMetadata(uuid string, enabled bool) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("SetMetadata", uuid, enabled)
+	if m.SetMetadataFunc == nil {
+		return
+	}
+
+	return m.SetMetadataFunc(uuid, enabled)
+}
+
+// TagServer will record the call and return the result of TagServerFunc
+func (m *MockClient) TagServer(uuid string, tags ...string) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("TagServer", uuid, tags)
+	if m.TagServerFunc == nil {
+		return
+	}
+
+	return m.TagServerFunc(uuid, tags...)
+}
+
+// UntagServer will record the call and return the result of UntagServerFunc
+func (m *MockClient) UntagServer(uuid string, tags ...string) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("UntagServer", uuid, tags)
+	if m.UntagServerFunc == nil {
+		return
+	}
+
+	return m.UntagServerFunc(uuid, tags...)
+}
+
+// StopServer will record the call and return the result of StopServerFunc
+func (m *MockClient) StopServer(uuid string, options upcloud.StopServer) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("StopServer", uuid, options)
+	if m.StopServerFunc == nil {
+		return
+	}
+
+	return m.StopServerFunc(uuid, options)
+}
+
+// StartServer will record the call and return the result of StartServerFunc
+func (m *MockClient) StartServer(uuid string, options upcloud.StartServer) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("StartServer", uuid, options)
+	if m.StartServerFunc == nil {
+		return
+	}
+
+	return m.StartServerFunc(uuid, options)
+}
+
+// DeleteServer will record the call and return the result of DeleteServerFunc
+func (m *MockClient) DeleteServer(uuid string, deleteStorage bool) (r0 error) {
+	m.record("DeleteServer", uuid, deleteStorage)
+	if m.DeleteServerFunc == nil {
+		return
+	}
+
+	return m.DeleteServerFunc(uuid, deleteStorage)
+}
+
+// DeleteServerWithOptions will record the call and return the result of DeleteServerWithOptionsFunc
+func (m *MockClient) DeleteServerWithOptions(ctx context.Context, uuid string, options upcloud.DeleteServer) (r0 error) {
+	m.record("DeleteServerWithOptions", ctx, uuid, options)
+	if m.DeleteServerWithOptionsFunc == nil {
+		return
+	}
+
+	return m.DeleteServerWithOptionsFunc(ctx, uuid, options)
+}
+
+// WaitForState will record the call and return the result of WaitForStateFunc
+func (m *MockClient) WaitForState(ctx context.Context, uuid string, state upcloud.ServerState) (r0 *upcloud.ServerDetails, r1 error) {
+	m.record("WaitForState", ctx, uuid, state)
+	if m.WaitForStateFunc == nil {
+		return
+	}
+
+	return m.WaitForStateFunc(ctx, uuid, state)
+}
+
+// ForEachServer will record the call and return the result of ForEachServerFunc
+func (m *MockClient) ForEachServer(ctx context.Context, filter upcloud.ServerFilter, fn upcloud.ServerFunc, concurrency int) (r0 []upcloud.BulkResult, r1 error) {
+	m.record("ForEachServer", ctx, filter, fn, concurrency)
+	if m.ForEachServerFunc == nil {
+		return
+	}
+
+	return m.ForEachServerFunc(ctx, filter, fn, concurrency)
+}
+
+// BulkStop will record the call and return the result of BulkStopFunc
+func (m *MockClient) BulkStop(ctx context.Context, uuids []string, stop upcloud.StopServer, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error) {
+	m.record("BulkStop", ctx, uuids, stop, options)
+	if m.BulkStopFunc == nil {
+		return
+	}
+
+	return m.BulkStopFunc(ctx, uuids, stop, options)
+}
+
+// BulkStart will record the call and return the result of BulkStartFunc
+func (m *MockClient) BulkStart(ctx context.Context, uuids []string, start upcloud.StartServer, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error) {
+	m.record("BulkStart", ctx, uuids, start, options)
+	if m.BulkStartFunc == nil {
+		return
+	}
+
+	return m.BulkStartFunc(ctx, uuids, start, options)
+}
+
+// BulkDelete will record the call and return the result of BulkDeleteFunc
+func (m *MockClient) BulkDelete(ctx context.Context, uuids []string, deleteStorage bool, options upcloud.BulkOptions) (r0 []upcloud.BulkResult, r1 error) {
+	m.record("BulkDelete", ctx, uuids, deleteStorage, options)
+	if m.BulkDeleteFunc == nil {
+		return
+	}
+
+	return m.BulkDeleteFunc(ctx, uuids, deleteStorage, options)
+}
+
+// CreateStorage will record the call and return the result of CreateStorageFunc
+func (m *MockClient) CreateStorage(options upcloud.CreateStorage) (r0 *upcloud.Storage, r1 error) {
+	m.record("CreateStorage", options)
+	if m.CreateStorageFunc == nil {
+		return
+	}
+
+	return m.CreateStorageFunc(options)
+}
+
+// DeleteStorage will record the call and return the result of DeleteStorageFunc
+func (m *MockClient) DeleteStorage(uuid string) (r0 error) {
+	m.record("DeleteStorage", uuid)
+	if m.DeleteStorageFunc == nil {
+		return
+	}
+
+	return m.DeleteStorageFunc(uuid)
+}
+
+// PlanReconcile will record the call and return the result of PlanReconcileFunc
+func (m *MockClient) PlanReconcile(ctx context.Context, spec *upcloud.Spec) (r0 *upcloud.ReconcilePlan, r1 error) {
+	m.record("PlanReconcile", ctx, spec)
+	if m.PlanReconcileFunc == nil {
+		return
+	}
+
+	return m.PlanReconcileFunc(ctx, spec)
+}
+
+// Reconcile will record the call and return the result of ReconcileFunc
+func (m *MockClient) Reconcile(ctx context.Context, spec *upcloud.Spec) (r0 *upcloud.ReconcilePlan, r1 error) {
+	m.record("Reconcile", ctx, spec)
+	if m.ReconcileFunc == nil {
+		return
+	}
+
+	return m.ReconcileFunc(ctx, spec)
+}
@@ -0,0 +1,39 @@
+package upcloudtest
+
+import (
+	"testing"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func TestMockClient(t *testing.T) {
+	var err error
+	var m MockClient
+	m.GetServerDetailsFunc = func(uuid string) (*upcloud.ServerDetails, error) {
+		return &upcloud.ServerDetails{UUID: uuid, State: upcloud.ServerStateStarted}, nil
+	}
+
+	var c upcloud.Client = &m
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = c.GetServerDetails("00334194-a6af-4fac-8eae-e098184c5e55"); err != nil {
+		t.Fatal(err)
+	}
+
+	if serverDetails.State != upcloud.ServerStateStarted {
+		t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", upcloud.ServerStateStarted, serverDetails.State)
+	}
+
+	// Methods without a func return zero values
+	if err = c.DeleteServer("00334194-a6af-4fac-8eae-e098184c5e55", true); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls = m.CallsTo("DeleteServer")
+	if len(calls) != 1 || calls[0].Args[0] != "00334194-a6af-4fac-8eae-e098184c5e55" || calls[0].Args[1] != true {
+		t.Fatalf("invalid calls, received %+v", calls)
+	}
+
+	if len(m.Calls()) != 2 {
+		t.Fatalf("invalid number of calls, expected %d and received %d", 2, len(m.Calls()))
+	}
+}
@@ -0,0 +1,132 @@
+package upcloudtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Redacted is the value recorded in place of passwords
+const Redacted = "[REDACTED]"
+
+var (
+	ipv4Expression = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)
+	ipv6Expression = regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){2,7}(?::|[0-9a-fA-F]{1,4})\b`)
+	macExpression  = regexp.MustCompile(`\b(?:[0-9a-fA-F]{2}:){5}[0-9a-fA-F]{2}\b`)
+)
+
+// Sanitize will return the JSON body with credentials, IP addresses, MAC addresses and passwords scrubbed
+// Note: Addresses are replaced by a hash of themselves, so the same address is always replaced by the
+// same value and relationships between recorded requests are kept
+func Sanitize(body []byte) []byte {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Bodies which are not JSON are scrubbed as plain text
+		return []byte(sanitizeString(string(body)))
+	}
+
+	bs, _ := json.Marshal(sanitizeValue("", value))
+	return bs
+}
+
+func sanitizeValue(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for childKey, child := range v {
+			v[childKey] = sanitizeValue(childKey, child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = sanitizeValue(key, child)
+		}
+	case string:
+		return sanitizeField(key, v)
+	}
+
+	return value
+}
+
+func sanitizeField(key, value string) string {
+	key = strings.ToLower(key)
+	switch {
+	case value == "":
+		return value
+	case strings.Contains(key, "password"):
+		return Redacted
+	case key == "username":
+		return Username
+	default:
+		return sanitizeString(value)
+	}
+}
+
+// sanitizeString will replace every IP and MAC address within the string
+// Note: MAC addresses are replaced first as they also match the IPv6 expression
+func sanitizeString(value string) string {
+	value = macExpression.ReplaceAllStringFunc(value, func(mac string) string {
+		var h = hash(mac)
+		return fmt.Sprintf("02:00:%02x:%02x:%02x:%02x", h>>24&0xff, h>>16&0xff, h>>8&0xff, h&0xff)
+	})
+
+	value = replaceIPv4(value, func(ip string) string {
+		var h = hash(ip)
+		return fmt.Sprintf("10.%d.%d.%d", h>>16&0xff, h>>8&0xff, h&0xff)
+	})
+
+	return ipv6Expression.ReplaceAllStringFunc(value, func(ip string) string {
+		if strings.HasPrefix(ip, "02:00:") || strings.HasPrefix(ip, "fd00:") {
+			// Already sanitized
+			return ip
+		}
+
+		var h = hash(ip)
+		return fmt.Sprintf("fd00::%x:%x", h>>16, h&0xffff)
+	})
+}
+
+// replaceIPv4 will replace every IPv4 address within the string
+// Note: Matches which are part of a longer dotted number (e.g. versions or OIDs) are kept
+func replaceIPv4(value string, replace func(ip string) string) string {
+	var out strings.Builder
+	var last int
+	for _, loc := range ipv4Expression.FindAllStringIndex(value, -1) {
+		var start, end = loc[0], loc[1]
+		if dottedNumber(value, start-2, start-1) || dottedNumber(value, end+1, end) {
+			continue
+		}
+
+		var ip = value[start:end]
+		if net.ParseIP(ip) == nil {
+			continue
+		}
+
+		out.WriteString(value[last:start])
+		out.WriteString(replace(ip))
+		last = end
+	}
+
+	out.WriteString(value[last:])
+	return out.String()
+}
+
+// dottedNumber will return whether or not the characters at the indexes are a digit and a dot
+func dottedNumber(value string, digit, dot int) bool {
+	if digit < 0 || dot < 0 || digit >= len(value) || dot >= len(value) {
+		return false
+	}
+
+	return value[dot] == '.' && value[digit] >= '0' && value[digit] <= '9'
+}
+
+func hash(value string) uint32 {
+	var h = fnv.New32a()
+	h.Write([]byte(value))
+	return h.Sum32()
+}
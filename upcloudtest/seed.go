@@ -0,0 +1,38 @@
+package upcloudtest
+
+import (
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+// Public template UUIDs seeded into every fake
+const (
+	TemplateUbuntu = "01000000-0000-4000-8000-000030200200"
+	TemplateDebian = "01000000-0000-4000-8000-000020060100"
+)
+
+// seed will populate the fake with a subset of the public UpCloud catalogue
+func (s *Server) seed() {
+	s.account = upcloud.Account{Username: Username, Credits: 10000}
+	s.zones = []upcloud.Zone{
+		{ID: "de-fra1", Description: "Frankfurt #1", Public: true},
+		{ID: "fi-hel1", Description: "Helsinki #1", Public: true},
+		{ID: "us-chi1", Description: "Chicago #1", Public: true},
+	}
+
+	s.plans = []upcloud.Plan{
+		{Name: "1xCPU-1GB", CoreNumber: 1, MemoryAmount: 1024, PublicTrafficOut: 1024, StorageSize: 25, StorageTier: upcloud.StorageTierMaxIOPS},
+		{Name: "1xCPU-2GB", CoreNumber: 1, MemoryAmount: 2048, PublicTrafficOut: 2048, StorageSize: 50, StorageTier: upcloud.StorageTierMaxIOPS},
+		{Name: "2xCPU-4GB", CoreNumber: 2, MemoryAmount: 4096, PublicTrafficOut: 4096, StorageSize: 80, StorageTier: upcloud.StorageTierMaxIOPS},
+	}
+
+	s.serverSizes = []upcloud.ServerSize{
+		{CoreNumber: 1, MemoryAmount: 1024},
+		{CoreNumber: 2, MemoryAmount: 4096},
+	}
+
+	s.timezones = []string{"Europe/Helsinki", "UTC"}
+	s.storages = []*upcloud.Storage{
+		{UUID: TemplateUbuntu, Title: "Ubuntu Server 20.04 LTS (Focal Fossa)", Access: upcloud.StorageAccessPublic, Type: upcloud.StorageTypeTemplate, State: upcloud.StorageStateOnline, Size: 4},
+		{UUID: TemplateDebian, Title: "Debian GNU/Linux 10 (Buster)", Access: upcloud.StorageAccessPublic, Type: upcloud.StorageTypeTemplate, State: upcloud.StorageStateOnline, Size: 4},
+	}
+}
@@ -0,0 +1,527 @@
+package upcloudtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+// server is a server of the fake along with its pending state transition
+type server struct {
+	sd            upcloud.ServerDetails
+	firewallRules []upcloud.FirewallRule
+
+	// State the server will be in once ready, empty when no transition is in progress
+	pending upcloud.ServerState
+	readyAt time.Time
+}
+
+// details will return a copy of the server details
+func (s *server) details() (sd *upcloud.ServerDetails) {
+	var bs, _ = json.Marshal(s.sd)
+	json.Unmarshal(bs, &sd)
+	return
+}
+
+// transition will complete every state transition which is ready
+func (s *Server) transition() {
+	var now = time.Now()
+	for _, srv := range s.servers {
+		if srv.pending == "" || now.Before(srv.readyAt) {
+			continue
+		}
+
+		srv.sd.State = srv.pending
+		srv.pending = ""
+	}
+}
+
+// setState will set the state of the server followed by the pending state once the transition delay elapses
+func (s *Server) setState(srv *server, state, pending upcloud.ServerState) {
+	srv.sd.State = state
+	srv.pending = pending
+	srv.readyAt = time.Now().Add(s.transitionDelay)
+}
+
+func (s *Server) findServer(uuid string) *server {
+	for _, srv := range s.servers {
+		if srv.sd.UUID == uuid {
+			return srv
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) findStorage(uuid string) *upcloud.Storage {
+	for _, storage := range s.storages {
+		if storage.UUID == uuid {
+			return storage
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) findPlan(name string) *upcloud.Plan {
+	for i, plan := range s.plans {
+		if plan.Name == name {
+			return &s.plans[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) hasZone(id string) bool {
+	for _, zone := range s.zones {
+		if zone.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) serveServer(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			s.getServers(w, r)
+		case "POST":
+			s.createServer(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "The method is not allowed.")
+		}
+
+		return
+	}
+
+	var srv = s.findServer(parts[0])
+	if srv == nil {
+		writeError(w, http.StatusNotFound, "SERVER_NOT_FOUND", fmt.Sprintf("The server %s does not exist.", parts[0]))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == "GET":
+		writeServer(w, http.StatusOK, srv)
+	case len(parts) == 1 && r.Method == "PUT":
+		s.modifyServer(w, r, srv)
+	case len(parts) == 1 && r.Method == "DELETE":
+		s.deleteServer(w, r, srv)
+	case len(parts) == 2 && r.Method == "POST" && parts[1] == "stop":
+		s.stopServer(w, r, srv)
+	case len(parts) == 2 && r.Method == "POST" && parts[1] == "start":
+		s.startServer(w, srv)
+	case len(parts) == 3 && r.Method == "POST" && parts[1] == "tag":
+		s.tagServer(w, srv, strings.Split(parts[2], ","))
+	case len(parts) == 3 && r.Method == "POST" && parts[1] == "untag":
+		s.untagServer(w, srv, strings.Split(parts[2], ","))
+	case len(parts) == 2 && r.Method == "GET" && parts[1] == "firewall_rule":
+		var rules = append([]upcloud.FirewallRule{}, srv.firewallRules...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"firewall_rules": upcloud.FirewallRules{FirewallRule: &rules}})
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("The route %s %s does not exist.", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) getServers(w http.ResponseWriter, r *http.Request) {
+	var servers = make([]upcloud.Server, 0, len(s.servers))
+	for _, srv := range s.servers {
+		servers = append(servers, upcloud.Server{
+			CoreNumber:    srv.sd.CoreNumber,
+			Hostname:      srv.sd.Hostname,
+			License:       srv.sd.License,
+			MemoryAmount:  srv.sd.MemoryAmount,
+			Plan:          srv.sd.Plan,
+			PlanIvp4Bytes: srv.sd.PlanIpv4Bytes,
+			PlanIpv6Bytes: srv.sd.PlanIpv6Bytes,
+			State:         srv.sd.State,
+			Tags:          srv.sd.Tags,
+			Title:         srv.sd.Title,
+			UUID:          srv.sd.UUID,
+			Zone:          srv.sd.Zone,
+		})
+	}
+
+	start, end, ok := page(w, r, len(servers))
+	if !ok {
+		return
+	}
+
+	servers = servers[start:end]
+	writeJSON(w, http.StatusOK, map[string]interface{}{"servers": upcloud.Servers{Server: &servers}})
+}
+
+func (s *Server) createServer(w http.ResponseWriter, r *http.Request) {
+	var sd *upcloud.ServerDetails
+	if !readServer(w, r, &sd) {
+		return
+	}
+
+	switch {
+	case !s.hasZone(sd.Zone):
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s is not valid.", sd.Zone))
+		return
+	case sd.Hostname == "":
+		writeError(w, http.StatusBadRequest, "HOSTNAME_MISSING", "The hostname is missing.")
+		return
+	case sd.Title == "":
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The title is missing.")
+		return
+	case len(sd.StorageDevices.List()) == 0:
+		writeError(w, http.StatusBadRequest, "STORAGE_DEVICES_MISSING", "The storage devices are missing.")
+		return
+	}
+
+	if !s.setPlan(w, sd) || !s.createStorages(w, sd) {
+		return
+	}
+
+	sd.UUID = s.newUUID("00")
+	s.createNetworking(sd)
+	if sd.LoginUser == nil || sd.LoginUser.CreatePassword == nil || bool(*sd.LoginUser.CreatePassword) {
+		sd.Password = fmt.Sprintf("pw%06d", s.counter)
+	}
+
+	// The login user is only used during creation
+	sd.LoginUser = nil
+	setDefaults(sd)
+
+	var srv server
+	srv.sd = *sd
+	s.setState(&srv, upcloud.ServerStateMaintenance, upcloud.ServerStateStarted)
+	s.servers = append(s.servers, &srv)
+	writeServer(w, http.StatusAccepted, &srv)
+
+	// The password is only returned once
+	srv.sd.Password = ""
+}
+
+// setPlan will set the size of the server from its plan, writing an error when the plan is invalid
+func (s *Server) setPlan(w http.ResponseWriter, sd *upcloud.ServerDetails) bool {
+	switch {
+	case sd.Plan == "" && sd.CoreNumber == 0:
+		// UpCloud defaults to the smallest plan
+		sd.Plan = s.plans[0].Name
+	case sd.Plan == "" || sd.Plan == "custom":
+		sd.Plan = "custom"
+		return true
+	}
+
+	var plan = s.findPlan(sd.Plan)
+	if plan == nil {
+		writeError(w, http.StatusBadRequest, "PLAN_INVALID", fmt.Sprintf("The plan %s is not valid.", sd.Plan))
+		return false
+	}
+
+	sd.CoreNumber = upcloud.StringInt(plan.CoreNumber)
+	sd.MemoryAmount = upcloud.StringInt(plan.MemoryAmount)
+	return true
+}
+
+// createStorages will create, clone or attach the storage devices, writing an error when a storage is invalid
+func (s *Server) createStorages(w http.ResponseWriter, sd *upcloud.ServerDetails) bool {
+	var devices []upcloud.StorageDevice
+	for i, device := range sd.StorageDevices.List() {
+		var storage *upcloud.Storage
+		switch device.Action {
+		case "create", "clone":
+			var origin *upcloud.Storage
+			if device.Action == "clone" {
+				if origin = s.findStorage(device.Storage); origin == nil {
+					writeError(w, http.StatusNotFound, "STORAGE_NOT_FOUND", fmt.Sprintf("The storage %s does not exist.", device.Storage))
+					return false
+				}
+			}
+
+			var tier = device.Tier
+			if tier == "" {
+				tier = upcloud.DefaultStorageTier
+			}
+
+			storage = &upcloud.Storage{
+				Access:  upcloud.StorageAccessPrivate,
+				Size:    device.StorageSize,
+				State:   upcloud.StorageStateOnline,
+				Tier:    tier,
+				Title:   device.Title,
+				Type:    upcloud.StorageTypeNormal,
+				UUID:    s.newUUID("01"),
+				Zone:    sd.Zone,
+				Created: time.Now().UTC(),
+			}
+
+			if origin != nil {
+				storage.Origin = origin.UUID
+				if storage.Size == 0 {
+					storage.Size = origin.Size
+				}
+			}
+
+			s.storages = append(s.storages, storage)
+		case "attach":
+			if storage = s.findStorage(device.Storage); storage == nil {
+				writeError(w, http.StatusNotFound, "STORAGE_NOT_FOUND", fmt.Sprintf("The storage %s does not exist.", device.Storage))
+				return false
+			}
+		default:
+			writeError(w, http.StatusBadRequest, "STORAGE_DEVICE_INVALID", fmt.Sprintf("The storage device action %s is not valid.", device.Action))
+			return false
+		}
+
+		devices = append(devices, upcloud.StorageDevice{
+			Address:      fmt.Sprintf("virtio:%d", i),
+			Storage:      storage.UUID,
+			StorageSize:  storage.Size,
+			StorageTitle: storage.Title,
+			Tier:         storage.Tier,
+			Type:         "disk",
+		})
+	}
+
+	sd.StorageDevices = &upcloud.StorageDevices{StorageDevice: &devices}
+	return true
+}
+
+// createNetworking will assign addresses to the interfaces of the server
+// Note: Servers without interfaces get the UpCloud defaults of public IPv4, utility IPv4 and public IPv6
+func (s *Server) createNetworking(sd *upcloud.ServerDetails) {
+	var interfaces = sd.Networking.InterfaceList()
+	if len(interfaces) == 0 {
+		interfaces = []upcloud.Interface{
+			{Type: "public", IPAddresses: &upcloud.IPAddresses{IPAddress: &[]upcloud.IPAddress{{Family: upcloud.IPv4}}}},
+			{Type: "utility", IPAddresses: &upcloud.IPAddresses{IPAddress: &[]upcloud.IPAddress{{Family: upcloud.IPv4}}}},
+			{Type: "public", IPAddresses: &upcloud.IPAddresses{IPAddress: &[]upcloud.IPAddress{{Family: upcloud.IPv6}}}},
+		}
+	}
+
+	var all []upcloud.IPAddress
+	for i := range interfaces {
+		var iface = &interfaces[i]
+		var addresses []upcloud.IPAddress
+		for _, address := range iface.IPAddresses.List() {
+			s.counter++
+			address.Address = fmt.Sprintf("10.%d.%d.%d", s.counter/65536%256, s.counter/256%256, s.counter%256)
+			if address.Family == upcloud.IPv6 {
+				address.Address = fmt.Sprintf("2a04:3540:1000:310::%x", s.counter)
+			}
+
+			address.Floating = upcloud.NewYesNo(false)
+			addresses = append(addresses, address)
+			all = append(all, upcloud.IPAddress{Access: iface.Type, Address: address.Address, Family: address.Family})
+		}
+
+		iface.Index = i + 1
+		iface.Mac = fmt.Sprintf("56:0b:73:%02x:%02x:%02x", s.counter/65536%256, s.counter/256%256, s.counter%256)
+		iface.IPAddresses = &upcloud.IPAddresses{IPAddress: &addresses}
+	}
+
+	sd.Networking = &upcloud.Networking{Interfaces: &upcloud.Interfaces{Interface: &interfaces}}
+	sd.IPAddresses = &upcloud.IPAddresses{IPAddress: &all}
+}
+
+func setDefaults(sd *upcloud.ServerDetails) {
+	if sd.Timezone == "" {
+		sd.Timezone = "UTC"
+	}
+
+	if sd.Firewall == nil {
+		sd.Firewall = upcloud.NewOnOff(false)
+	}
+
+	if sd.Metadata == nil {
+		sd.Metadata = upcloud.NewYesNo(false)
+	}
+
+	if sd.RemoteAccessEnabled == nil {
+		sd.RemoteAccessEnabled = upcloud.NewYesNo(false)
+	}
+
+	if sd.Tags == nil {
+		sd.Tags = &upcloud.Tags{Tag: &[]string{}}
+	}
+
+	sd.BootOrder = "disk"
+	sd.NicModel = "virtio"
+	sd.VideoModel = "cirrus"
+	sd.RemoteAccessType = "vnc"
+	sd.SimpleBackup = "no"
+}
+
+func (s *Server) modifyServer(w http.ResponseWriter, r *http.Request, srv *server) {
+	var sd *upcloud.ServerDetails
+	if !readServer(w, r, &sd) {
+		return
+	}
+
+	var current = &srv.sd
+	if sd.Title != "" {
+		current.Title = sd.Title
+	}
+
+	if sd.Hostname != "" {
+		current.Hostname = sd.Hostname
+	}
+
+	if sd.Plan != "" || sd.CoreNumber != 0 {
+		if srv.sd.State != upcloud.ServerStateStopped {
+			writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL", "The server must be stopped to change its size.")
+			return
+		}
+
+		if !s.setPlan(w, sd) {
+			return
+		}
+
+		current.Plan, current.CoreNumber, current.MemoryAmount = sd.Plan, sd.CoreNumber, sd.MemoryAmount
+	}
+
+	if sd.Metadata != nil {
+		current.Metadata = sd.Metadata
+	}
+
+	if sd.Firewall != nil {
+		current.Firewall = sd.Firewall
+	}
+
+	if sd.Timezone != "" {
+		current.Timezone = sd.Timezone
+	}
+
+	writeServer(w, http.StatusAccepted, srv)
+}
+
+// tagServer will add the tags the server does not carry yet
+// Note: Tags are case-insensitive
+func (s *Server) tagServer(w http.ResponseWriter, srv *server, tags []string) {
+	var current = append([]string{}, srv.sd.Tags.List()...)
+	for _, tag := range tags {
+		if !containsTag(current, tag) {
+			current = append(current, tag)
+		}
+	}
+
+	srv.sd.Tags = &upcloud.Tags{Tag: &current}
+	writeServer(w, http.StatusOK, srv)
+}
+
+// untagServer will remove the tags from the server
+func (s *Server) untagServer(w http.ResponseWriter, srv *server, tags []string) {
+	var remaining = []string{}
+	for _, tag := range srv.sd.Tags.List() {
+		if !containsTag(tags, tag) {
+			remaining = append(remaining, tag)
+		}
+	}
+
+	srv.sd.Tags = &upcloud.Tags{Tag: &remaining}
+	writeServer(w, http.StatusOK, srv)
+}
+
+func (s *Server) stopServer(w http.ResponseWriter, r *http.Request, srv *server) {
+	var req struct {
+		StopServer upcloud.StopServer `json:"stop_server"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON_MALFORMED", "The request body is malformed.")
+		return
+	}
+
+	var options = req.StopServer
+	if options.StopType != "" && options.StopType != string(upcloud.Soft) && options.StopType != string(upcloud.Hard) {
+		writeError(w, http.StatusBadRequest, "STOP_TYPE_INVALID", fmt.Sprintf("The stop type %s is invalid.", options.StopType))
+		return
+	}
+
+	if options.Timeout != "" {
+		if timeout, err := strconv.Atoi(options.Timeout); err != nil || timeout < 1 || timeout > 600 {
+			writeError(w, http.StatusBadRequest, "TIMEOUT_INVALID", fmt.Sprintf("The timeout %s is invalid.", options.Timeout))
+			return
+		}
+	}
+
+	if srv.sd.State != upcloud.ServerStateStarted {
+		writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL", fmt.Sprintf("The server is in %s state.", srv.sd.State))
+		return
+	}
+
+	s.setState(srv, upcloud.ServerStateStarted, upcloud.ServerStateStopped)
+	writeServer(w, http.StatusAccepted, srv)
+}
+
+func (s *Server) startServer(w http.ResponseWriter, srv *server) {
+	if srv.sd.State != upcloud.ServerStateStopped {
+		writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL", fmt.Sprintf("The server is in %s state.", srv.sd.State))
+		return
+	}
+
+	s.setState(srv, upcloud.ServerStateMaintenance, upcloud.ServerStateStarted)
+	writeServer(w, http.StatusAccepted, srv)
+}
+
+func (s *Server) deleteServer(w http.ResponseWriter, r *http.Request, srv *server) {
+	if srv.sd.State != upcloud.ServerStateStopped {
+		writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL", fmt.Sprintf("The server is in %s state.", srv.sd.State))
+		return
+	}
+
+	var backups = r.URL.Query().Get("backups")
+	if !validBackups(backups) {
+		writeError(w, http.StatusBadRequest, "BACKUPS_INVALID", fmt.Sprintf("The backups value %s is invalid.", backups))
+		return
+	}
+
+	if r.URL.Query().Get("storages") == "1" {
+		for _, device := range srv.sd.StorageDevices.List() {
+			if device.Type == "disk" {
+				s.deleteStorage(device.Storage, backups)
+			}
+		}
+	}
+
+	for i, candidate := range s.servers {
+		if candidate == srv {
+			s.servers = append(s.servers[:i], s.servers[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readServer will decode the server details of the request body, writing an error when the body is invalid
+func readServer(w http.ResponseWriter, r *http.Request, sd **upcloud.ServerDetails) bool {
+	var req struct {
+		Server *upcloud.ServerDetails `json:"server"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Server == nil {
+		writeError(w, http.StatusBadRequest, "JSON_MALFORMED", "The request body is malformed.")
+		return false
+	}
+
+	*sd = req.Server
+	return true
+}
+
+func writeServer(w http.ResponseWriter, statusCode int, srv *server) {
+	writeJSON(w, statusCode, map[string]interface{}{"server": srv.details()})
+}
@@ -0,0 +1,188 @@
+package upcloudtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func (s *Server) serveStorage(w http.ResponseWriter, r *http.Request, path string, parts []string) {
+	switch {
+	case r.Method == "GET":
+		s.getStorages(w, r, path)
+	case r.Method == "POST" && len(parts) == 0:
+		s.createStorage(w, r)
+	case r.Method == "DELETE" && len(parts) == 1:
+		s.deleteStorageRoute(w, r, parts[0])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("The route %s %s does not exist.", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) getStorages(w http.ResponseWriter, r *http.Request, path string) {
+	var storages = make([]upcloud.Storage, 0, len(s.storages))
+	for _, storage := range s.storages {
+		if matchStorageFilter(upcloud.RouteGetStorageFilter(path), storage) {
+			storages = append(storages, *storage)
+		}
+	}
+
+	start, end, ok := page(w, r, len(storages))
+	if !ok {
+		return
+	}
+
+	storages = storages[start:end]
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"storages": upcloud.Storages{Storage: &storages}})
+}
+
+func matchStorageFilter(filter upcloud.RouteGetStorageFilter, storage *upcloud.Storage) bool {
+	switch filter {
+	case upcloud.All:
+		return true
+	case upcloud.Public:
+		return storage.Access == upcloud.StorageAccessPublic
+	case upcloud.Private:
+		return storage.Access == upcloud.StorageAccessPrivate
+	case upcloud.Normal:
+		return storage.Type == upcloud.StorageTypeNormal
+	case upcloud.Backup:
+		return storage.Type == upcloud.StorageTypeBackup
+	case upcloud.Cdrom:
+		return storage.Type == upcloud.StorageTypeCdrom
+	case upcloud.Template:
+		return storage.Type == upcloud.StorageTypeTemplate
+	default:
+		return false
+	}
+}
+
+func (s *Server) createStorage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Storage *upcloud.CreateStorage `json:"storage"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Storage == nil {
+		writeError(w, http.StatusBadRequest, "JSON_MALFORMED", "The request body is malformed.")
+		return
+	}
+
+	switch {
+	case !s.hasZone(req.Storage.Zone):
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s is not valid.", req.Storage.Zone))
+		return
+	case req.Storage.Size < 1 || req.Storage.Size > 4096:
+		writeError(w, http.StatusBadRequest, "SIZE_INVALID", "The size must be between 1 and 4096 gigabytes.")
+		return
+	case req.Storage.Title == "":
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The title is missing.")
+		return
+	}
+
+	var tier = req.Storage.Tier
+	if tier == "" {
+		tier = upcloud.StorageTierHDD
+	}
+
+	var storage = upcloud.Storage{
+		Access:  upcloud.StorageAccessPrivate,
+		Size:    req.Storage.Size,
+		State:   upcloud.StorageStateOnline,
+		Tier:    tier,
+		Title:   req.Storage.Title,
+		Type:    upcloud.StorageTypeNormal,
+		UUID:    s.newUUID("01"),
+		Zone:    req.Storage.Zone,
+		Created: time.Now().UTC(),
+	}
+
+	s.storages = append(s.storages, &storage)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"storage": storage})
+}
+
+func (s *Server) deleteStorageRoute(w http.ResponseWriter, r *http.Request, uuid string) {
+	var storage = s.findStorage(uuid)
+	var backups = r.URL.Query().Get("backups")
+	switch {
+	case !validBackups(backups):
+		writeError(w, http.StatusBadRequest, "BACKUPS_INVALID", fmt.Sprintf("The backups value %s is invalid.", backups))
+		return
+	case storage == nil:
+		writeError(w, http.StatusNotFound, "STORAGE_NOT_FOUND", fmt.Sprintf("The storage %s does not exist.", uuid))
+		return
+	case storage.Type == upcloud.StorageTypeTemplate && storage.Access == upcloud.StorageAccessPublic:
+		writeError(w, http.StatusForbidden, "STORAGE_FORBIDDEN", "Public templates cannot be deleted.")
+		return
+	case s.isAttached(uuid):
+		writeError(w, http.StatusConflict, "STORAGE_ATTACHED", fmt.Sprintf("The storage %s is attached to a server.", uuid))
+		return
+	}
+
+	s.deleteStorage(uuid, backups)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteStorage will delete the storage and its backups according to the backups value (keep, keep_latest or delete)
+// Note: Backups are kept by default
+func (s *Server) deleteStorage(uuid, backups string) {
+	var latest *upcloud.Storage
+	for _, storage := range s.storages {
+		if isBackupOf(storage, uuid) && (latest == nil || storage.Created.After(latest.Created)) {
+			latest = storage
+		}
+	}
+
+	var keep = func(storage *upcloud.Storage) bool {
+		switch {
+		case storage.UUID == uuid:
+			return false
+		case !isBackupOf(storage, uuid):
+			return true
+		case backups == "delete":
+			return false
+		case backups == "keep_latest":
+			return storage == latest
+		default:
+			return true
+		}
+	}
+
+	var remaining = make([]*upcloud.Storage, 0, len(s.storages))
+	for _, storage := range s.storages {
+		if keep(storage) {
+			remaining = append(remaining, storage)
+		}
+	}
+
+	s.storages = remaining
+}
+
+func isBackupOf(storage *upcloud.Storage, uuid string) bool {
+	return storage.Type == upcloud.StorageTypeBackup && storage.Origin == uuid
+}
+
+func validBackups(backups string) bool {
+	switch backups {
+	case "", "keep", "keep_latest", "delete":
+		return true
+	default:
+		return false
+	}
+}
+
+// isAttached will return whether or not the storage is attached to a server
+func (s *Server) isAttached(uuid string) bool {
+	for _, srv := range s.servers {
+		for _, device := range srv.sd.StorageDevices.List() {
+			if device.Storage == uuid {
+				return true
+			}
+		}
+	}
+
+	return false
+}
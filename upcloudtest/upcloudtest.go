@@ -0,0 +1,352 @@
+// Package upcloudtest provides an in-memory fake of the UpCloud API for tests
+//
+// The fake keeps state between requests, so full server lifecycles (create,
+// stop, start, delete) can be tested offline:
+//
+//	srv := upcloudtest.NewServer()
+//	defer srv.Close()
+//
+//	serverDetails, err := upcloud.NewServerBuilder("fi-hel1", "web").
+//		CloneTemplate(upcloudtest.TemplateUbuntu, "web-disk", 25).
+//		Build()
+//
+//	server, err := srv.Client().CreateServer(serverDetails)
+package upcloudtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hatchify/requester"
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+const (
+	// Username is the username accepted by the fake
+	Username = "username"
+	// Password is the password accepted by the fake
+	Password = "password"
+)
+
+// Failure represents an error response returned by the fake instead of handling the request
+type Failure struct {
+	// Method to match, matches every method when empty
+	Method string
+	// Path prefix to match, relative to the API version (e.g. "server" matches "server/<uuid>/stop")
+	Path string
+
+	StatusCode   int
+	ErrorCode    string
+	ErrorMessage string
+
+	// Times is the number of requests to fail, every matching request fails when zero
+	Times int
+}
+
+func (f *Failure) match(method, path string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+
+	return strings.HasPrefix(path, f.Path)
+}
+
+// NewServer will return a new fake UpCloud API seeded with zones, plans, server sizes and public templates
+// Note: The server must be closed once done
+func NewServer() *Server {
+	var s Server
+	s.seed()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return &s
+}
+
+// Server is a stateful fake of the UpCloud API
+type Server struct {
+	*httptest.Server
+
+	mux sync.Mutex
+
+	account     upcloud.Account
+	zones       []upcloud.Zone
+	plans       []upcloud.Plan
+	serverSizes []upcloud.ServerSize
+	timezones   []string
+
+	// Servers, storages and networks in creation order
+	servers  []*server
+	storages []*upcloud.Storage
+	networks []upcloud.Network
+
+	failures        []*Failure
+	latency         time.Duration
+	transitionDelay time.Duration
+
+	// Counter used for UUIDs, IP addresses and passwords
+	counter int
+}
+
+// Client will return a new UpCloud client which sends requests to the fake
+func (s *Server) Client() *upcloud.UpCloud {
+	// Note: New does not return an error
+	u, _ := upcloud.New(Username, Password)
+	u.SetRequester(requester.New(s.Server.Client(), s.URL))
+	return u
+}
+
+// Fail will return the failure for matching requests
+// Note: Failures are matched in the order they were added
+func (s *Server) Fail(f Failure) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusInternalServerError
+	}
+
+	if f.ErrorCode == "" {
+		f.ErrorCode = "INTERNAL_ERROR"
+	}
+
+	if f.ErrorMessage == "" {
+		f.ErrorMessage = "Injected failure."
+	}
+
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures will remove every failure
+func (s *Server) ClearFailures() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failures = nil
+}
+
+// SetLatency will delay every response by the provided duration
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.latency = latency
+}
+
+// SetTransitionDelay will set how long servers stay in a transitional state (e.g. maintenance)
+// Note: With no delay the transition completes before the next request is handled
+func (s *Server) SetTransitionDelay(delay time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.transitionDelay = delay
+}
+
+// SetAccount will set the account returned by the fake
+func (s *Server) SetAccount(account upcloud.Account) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.account = account
+}
+
+// AddZone will add a zone to the fake
+func (s *Server) AddZone(zone upcloud.Zone) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.zones = append(s.zones, zone)
+}
+
+// AddPlan will add a plan to the fake
+func (s *Server) AddPlan(plan upcloud.Plan) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.plans = append(s.plans, plan)
+}
+
+// AddStorage will add a storage to the fake, a UUID is generated when not set
+func (s *Server) AddStorage(storage upcloud.Storage) upcloud.Storage {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if storage.UUID == "" {
+		storage.UUID = s.newUUID("01")
+	}
+
+	s.storages = append(s.storages, &storage)
+	return storage
+}
+
+// AddNetwork will add a network to the fake, a UUID is generated when not set
+func (s *Server) AddNetwork(network upcloud.Network) upcloud.Network {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if network.UUID == "" {
+		network.UUID = s.newUUID("03")
+	}
+
+	s.networks = append(s.networks, network)
+	return network
+}
+
+// SetFirewallRules will set the firewall rules of a server
+// Note: False is returned when the server does not exist
+func (s *Server) SetFirewallRules(uuid string, rules []upcloud.FirewallRule) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var srv = s.findServer(uuid)
+	if srv == nil {
+		return false
+	}
+
+	srv.firewallRules = append([]upcloud.FirewallRule{}, rules...)
+	return true
+}
+
+// Servers will return a copy of the details of every server
+func (s *Server) Servers() (servers []upcloud.ServerDetails) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.transition()
+	for _, srv := range s.servers {
+		servers = append(servers, *srv.details())
+	}
+
+	return
+}
+
+// Storages will return a copy of every storage
+func (s *Server) Storages() (storages []upcloud.Storage) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, storage := range s.storages {
+		storages = append(storages, *storage)
+	}
+
+	return
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	var latency = s.latency
+	s.mux.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if username, password, ok := r.BasicAuth(); !ok || username != Username || password != Password {
+		writeError(w, http.StatusUnauthorized, "AUTHENTICATION_FAILED", "Authentication failed using the given username and password.")
+		return
+	}
+
+	var path = strings.Trim(strings.TrimPrefix(r.URL.Path, "/"+upcloud.APIVersion), "/")
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if f := s.failure(r.Method, path); f != nil {
+		writeError(w, f.StatusCode, f.ErrorCode, f.ErrorMessage)
+		return
+	}
+
+	s.transition()
+
+	var parts = strings.Split(path, "/")
+	switch {
+	case r.Method == "GET" && path == upcloud.RouteGetAccount:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"account": s.account})
+	case r.Method == "GET" && path == upcloud.RouteGetZone:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"zones": upcloud.Zones{Zone: &s.zones}})
+	case r.Method == "GET" && path == upcloud.RouteGetPlan:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"plans": upcloud.Plans{Plan: &s.plans}})
+	case r.Method == "GET" && path == upcloud.RouteGetServerSize:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"server_sizes": upcloud.ServerSizes{ServerSize: &s.serverSizes}})
+	case r.Method == "GET" && path == upcloud.RouteGetTimezone:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"timezones": upcloud.Timezones{Timezone: &s.timezones}})
+	case r.Method == "GET" && path == upcloud.RouteNetwork:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"networks": upcloud.Networks{Network: &s.networks}})
+	case parts[0] == upcloud.RouteStorage:
+		s.serveStorage(w, r, path, parts[1:])
+	case parts[0] == upcloud.RouteServer:
+		s.serveServer(w, r, parts[1:])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("The route %s %s does not exist.", r.Method, r.URL.Path))
+	}
+}
+
+// failure will return the first failure matching the request, if any
+func (s *Server) failure(method, path string) *Failure {
+	for i, f := range s.failures {
+		if !f.match(method, path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// page will return the bounds of the page selected by the limit and offset query parameters
+// Note: Invalid parameters are answered with a 400 error, in which case ok is false
+func page(w http.ResponseWriter, r *http.Request, n int) (start, end int, ok bool) {
+	var limit int
+	if limit, ok = queryInt(w, r, "limit", "LIMIT_INVALID"); !ok {
+		return
+	}
+
+	if start, ok = queryInt(w, r, "offset", "OFFSET_INVALID"); !ok {
+		return
+	}
+
+	if start > n {
+		start = n
+	}
+
+	end = n
+	if limit > 0 && start+limit < n {
+		end = start + limit
+	}
+
+	return
+}
+
+// queryInt will return the non-negative integer of a query parameter, 0 when not set
+func queryInt(w http.ResponseWriter, r *http.Request, key, code string) (value int, ok bool) {
+	var str = r.URL.Query().Get(key)
+	if str == "" {
+		return 0, true
+	}
+
+	var err error
+	if value, err = strconv.Atoi(str); err != nil || value < 0 {
+		writeError(w, http.StatusBadRequest, code, fmt.Sprintf("The %s %s is invalid.", key, str))
+		return 0, false
+	}
+
+	return value, true
+}
+
+func (s *Server) newUUID(prefix string) string {
+	s.counter++
+	return fmt.Sprintf("%s000000-0000-4000-8000-%012d", prefix, s.counter)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": upcloud.Error{Code: code, Message: message},
+	})
+}
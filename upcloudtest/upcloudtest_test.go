@@ -0,0 +1,91 @@
+package upcloudtest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+)
+
+func TestServer_Fail(t *testing.T) {
+	var err error
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Fail(Failure{Method: "GET", Path: upcloud.RouteGetZone, StatusCode: http.StatusServiceUnavailable, ErrorCode: "SERVICE_UNAVAILABLE", Times: 1})
+
+	u := srv.Client()
+	_, err = u.GetZones()
+	if e, ok := err.(*upcloud.Error); !ok || e.Code != "SERVICE_UNAVAILABLE" {
+		t.Fatalf("invalid error, expected SERVICE_UNAVAILABLE and received %v", err)
+	}
+
+	var zones *[]upcloud.Zone
+	if zones, err = u.GetZones(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*zones) != 3 {
+		t.Fatalf("invalid number of zones, expected %d and received %d", 3, len(*zones))
+	}
+}
+
+func TestServer_SetTransitionDelay(t *testing.T) {
+	var err error
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetTransitionDelay(time.Hour)
+
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("fi-hel1", "web").AddDisk("web-disk", 10, upcloud.StorageTierHDD).Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	u := srv.Client()
+	if serverDetails, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	if serverDetails, err = u.GetServerDetails(serverDetails.UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	if serverDetails.State != upcloud.ServerStateMaintenance {
+		t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", upcloud.ServerStateMaintenance, serverDetails.State)
+	}
+
+	if serverDetails.Password != "" {
+		t.Fatal("expected password to only be returned on creation")
+	}
+
+	if len(serverDetails.Networking.InterfaceList()) != 3 {
+		t.Fatalf("invalid number of default interfaces, received %+v", serverDetails.Networking.InterfaceList())
+	}
+}
+
+func TestServer_InvalidPage(t *testing.T) {
+	var err error
+	srv := NewServer()
+	defer srv.Close()
+
+	for _, query := range []string{"offset=-1", "limit=-1", "offset=first"} {
+		var req *http.Request
+		if req, err = http.NewRequest("GET", srv.URL+"/"+upcloud.APIVersion+"/"+upcloud.RouteServer+"?"+query, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		req.SetBasicAuth(Username, Password)
+
+		var res *http.Response
+		if res, err = srv.Server.Client().Do(req); err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+		if res.StatusCode != http.StatusBadRequest {
+			t.Fatalf("invalid status code for %s, expected %d and received %d", query, http.StatusBadRequest, res.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package upcloud_test
+
+import (
+	"testing"
+
+	upcloud "github.com/hatchify/upcloud-sdk"
+	"github.com/hatchify/upcloud-sdk/upcloudtest"
+)
+
+func createServer(t *testing.T, u *upcloud.UpCloud) (s *upcloud.ServerDetails) {
+	var err error
+	var serverDetails *upcloud.ServerDetails
+	if serverDetails, err = upcloud.NewServerBuilder("us-chi1", "sdk-test-machine").
+		Plan("1xCPU-1GB").
+		CloneTemplate(upcloudtest.TemplateUbuntu, "sdk-test-disk", 25).
+		PublicIPv4().
+		Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = u.CreateServer(serverDetails); err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+func TestUpCloud_CreateServerWithMocks(t *testing.T) {
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	s := createServer(t, srv.Client())
+	if s.Hostname != "sdk-test-machine" {
+		t.Fatalf("invalid hostname, expected \"%s\" and received \"%s\"", "sdk-test-machine", s.Hostname)
+	}
+
+	if s.State != upcloud.ServerStateMaintenance {
+		t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", upcloud.ServerStateMaintenance, s.State)
+	}
+
+	if s.Password == "" {
+		t.Fatal("expected generated password within the response")
+	}
+
+	if devices := s.StorageDevices.List(); len(devices) != 1 || devices[0].StorageSize != 25 {
+		t.Fatalf("invalid storage devices, received %+v", devices)
+	}
+}
+
+func TestUpCloud_FullServerCreationCycleWithMocks(t *testing.T) {
+	var err error
+	srv := upcloudtest.NewServer()
+	defer srv.Close()
+
+	u := srv.Client()
+	uuid := createServer(t, u).UUID
+
+	expectState := func(t *testing.T, state upcloud.ServerState) {
+		var s *upcloud.ServerDetails
+		if s, err = u.GetServerDetails(uuid); err != nil {
+			t.Fatal(err)
+		}
+
+		if s.State != state {
+			t.Fatalf("invalid state, expected \"%s\" and received \"%s\"", state, s.State)
+		}
+	}
+
+	t.Run("started", func(t *testing.T) {
+		expectState(t, upcloud.ServerStateStarted)
+	})
+
+	t.Run("delete started", func(t *testing.T) {
+		err = u.DeleteServer(uuid, true)
+		if e, ok := err.(*upcloud.Error); !ok || e.Code != "SERVER_STATE_ILLEGAL" {
+			t.Fatalf("invalid error, expected SERVER_STATE_ILLEGAL and received %v", err)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		if _, err = u.StopServer(uuid, upcloud.StopServer{StopType: string(upcloud.Soft)}); err != nil {
+			t.Fatal(err)
+		}
+
+		expectState(t, upcloud.ServerStateStopped)
+	})
+
+	t.Run("start", func(t *testing.T) {
+		if _, err = u.StartServer(uuid, upcloud.StartServer{}); err != nil {
+			t.Fatal(err)
+		}
+
+		expectState(t, upcloud.ServerStateStarted)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if _, err = u.StopServer(uuid, upcloud.StopServer{StopType: string(upcloud.Hard)}); err != nil {
+			t.Fatal(err)
+		}
+
+		expectState(t, upcloud.ServerStateStopped)
+		if err = u.DeleteServer(uuid, true); err != nil {
+			t.Fatal(err)
+		}
+
+		var servers *[]upcloud.Server
+		if servers, err = u.GetServers(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*servers) != 0 {
+			t.Fatalf("invalid number of servers, expected %d and received %d", 0, len(*servers))
+		}
+
+		var storages *[]upcloud.Storage
+		if storages, err = u.GetStorages(upcloud.Private); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*storages) != 0 {
+			t.Fatalf("invalid number of private storages, expected %d and received %d", 0, len(*storages))
+		}
+	})
+}
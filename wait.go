@@ -0,0 +1,99 @@
+package upcloud
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is the interval between requests while waiting for a server state
+	DefaultPollInterval = 5 * time.Second
+	// DefaultStopTimeout is the time soft stops give servers to shut down before UpCloud stops them forcibly
+	DefaultStopTimeout = time.Minute
+	// MaxStopTimeout is the longest stop timeout accepted by UpCloud
+	MaxStopTimeout = 600 * time.Second
+)
+
+// SetPollInterval will set the interval between requests while waiting for a server state
+func (u *UpCloud) SetPollInterval(interval time.Duration) {
+	u.pollInterval = interval
+}
+
+// WaitForState will poll the server details until the server is in the provided state
+// Note: The context bounds how long to wait, an error is returned when the server enters the error state
+func (u *UpCloud) WaitForState(ctx context.Context, uuid string, state ServerState) (s *ServerDetails, err error) {
+	var interval = u.pollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if s, err = u.GetServerDetails(uuid); err != nil {
+			return
+		}
+
+		switch s.State {
+		case state:
+			return
+		case ServerStateError:
+			err = fmt.Errorf("server %s entered the error state while waiting for the %s state", uuid, state)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+}
+
+// stopAndWait will stop the server unless already stopped and wait for the stopped state
+// Note: Soft stops fall back to a hard stop once the timeout elapses, see newStopServer
+func (u *UpCloud) stopAndWait(ctx context.Context, uuid string, stopType ServerStopType, timeout time.Duration) (err error) {
+	var sd *ServerDetails
+	if sd, err = u.GetServerDetails(uuid); err != nil {
+		return
+	}
+
+	if sd.State == ServerStateStopped {
+		return
+	}
+
+	if _, err = u.StopServer(uuid, newStopServer(stopType, timeout)); err != nil {
+		return
+	}
+
+	_, err = u.WaitForState(ctx, uuid, ServerStateStopped)
+	return
+}
+
+// newStopServer will return the options of a stop, soft stops are given the timeout to shut down
+// Note: A soft stop is used when the type is empty and DefaultStopTimeout when the timeout is not set.
+// Timeouts are rounded up to whole seconds and capped at MaxStopTimeout
+func newStopServer(stopType ServerStopType, timeout time.Duration) (s StopServer) {
+	if stopType == "" {
+		stopType = Soft
+	}
+
+	s.StopType = string(stopType)
+	if stopType != Soft {
+		return
+	}
+
+	switch {
+	case timeout <= 0:
+		timeout = DefaultStopTimeout
+	case timeout > MaxStopTimeout:
+		timeout = MaxStopTimeout
+	}
+
+	s.Timeout = strconv.Itoa(int((timeout + time.Second - 1) / time.Second))
+	return
+}
@@ -0,0 +1,25 @@
+package upcloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewStopServer(t *testing.T) {
+	var cases = []struct {
+		stopType ServerStopType
+		timeout  time.Duration
+		expected StopServer
+	}{
+		{"", 0, StopServer{StopType: "soft", Timeout: "60"}},
+		{Soft, 1500 * time.Millisecond, StopServer{StopType: "soft", Timeout: "2"}},
+		{Soft, time.Hour, StopServer{StopType: "soft", Timeout: "600"}},
+		{Hard, time.Minute, StopServer{StopType: "hard"}},
+	}
+
+	for _, c := range cases {
+		if s := newStopServer(c.stopType, c.timeout); s != c.expected {
+			t.Fatalf("invalid stop options for %s %v, expected %+v and received %+v", c.stopType, c.timeout, c.expected, s)
+		}
+	}
+}
@@ -1,10 +1,35 @@
 package upcloud
 
+import (
+	"errors"
+	"strings"
+)
+
+// ErrZoneNotFound is returned when a zone does not exist
+var ErrZoneNotFound = errors.New("zone not found")
+
 // Zone represents UpCloud zone
 type Zone struct {
 	Description string `json:"description"`
 	ID          string `json:"id"`
-	Public      string `json:"public"`
+	Public      YesNo  `json:"public"`
+	// Public zone hosting a private cloud zone
+	ParentZone string `json:"parent_zone,omitempty"`
+}
+
+// IsPublic will return whether or not the zone is a public zone
+func (z *Zone) IsPublic() bool {
+	return bool(z.Public)
+}
+
+// IsPrivate will return whether or not the zone is a private cloud zone
+func (z *Zone) IsPrivate() bool {
+	return !z.IsPublic()
+}
+
+// Country will return the country code of the zone (e.g. "de" for "de-fra1")
+func (z *Zone) Country() string {
+	return zoneCountry(z.ID)
 }
 
 // Zones represents all UpCloud zones
@@ -12,7 +37,26 @@ type Zones struct {
 	Zone *[]Zone `json:"zone"`
 }
 
+// List will return the zones as a slice
+// Note: A nil slice is returned when the value or zones are not set
+func (z *Zones) List() []Zone {
+	if z == nil || z.Zone == nil {
+		return nil
+	}
+
+	return *z.Zone
+}
+
 // getZonesResponse is a response wrapper to match the UpCloud API payload
 type getZonesResponse struct {
 	Zones *Zones `json:"zones"`
 }
+
+func zoneCountry(id string) string {
+	var idx int
+	if idx = strings.IndexByte(id, '-'); idx == -1 {
+		return id
+	}
+
+	return id[:idx]
+}